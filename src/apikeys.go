@@ -0,0 +1,325 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// apiKeyPrefix marks blogo API keys so they are easy to recognise in
+// configuration files and secret scanners
+const apiKeyPrefix = "blogo_"
+
+var errInvalidToken = errors.New("invalid or revoked token")
+
+// APIKey describes an issued API key. The key itself is only shown once,
+// when it is created; only its SHA-256 hash is stored.
+// @swagger:model
+type APIKey struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Role string `json:"role" enums:"viewer,author,editor,admin"`
+
+	// The first characters of the key, to tell keys apart
+	Prefix string `json:"prefix"`
+
+	// The full key, only returned when the key is created
+	Key string `json:"key,omitempty"`
+
+	CreatedAt  string  `json:"created_at"`
+	LastUsedAt *string `json:"last_used_at,omitempty"`
+	RevokedAt  *string `json:"revoked_at,omitempty"`
+}
+
+// randomToken returns a prefixed, URL-safe token with 256 bits of randomness
+func randomToken(prefix string) (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return prefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// hashToken hashes an API key or refresh token for storage and lookup.
+// Tokens are long and random, so a fast hash is enough; there is nothing
+// to brute-force.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// issueAPIKey creates a new key with the given role and returns it,
+// including the plain key
+func issueAPIKey(name, role string) (APIKey, error) {
+	if _, ok := roleRank[role]; !ok {
+		return APIKey{}, fmt.Errorf("invalid role: must be viewer, author, editor or admin")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 100 {
+		return APIKey{}, fmt.Errorf("name is required and cannot exceed 100 characters")
+	}
+
+	key, err := randomToken(apiKeyPrefix)
+	if err != nil {
+		return APIKey{}, err
+	}
+
+	issued := APIKey{Name: name, Role: role, Prefix: key[:len(apiKeyPrefix)+6], Key: key}
+	result, err := db.Exec("INSERT INTO api_keys (name, role, prefix, key_hash) VALUES (?, ?, ?, ?)",
+		issued.Name, issued.Role, issued.Prefix, hashToken(key))
+	if err != nil {
+		return APIKey{}, err
+	}
+	if issued.ID, err = result.LastInsertId(); err != nil {
+		return APIKey{}, err
+	}
+	err = db.QueryRow("SELECT created_at FROM api_keys WHERE id = ?", issued.ID).Scan(&issued.CreatedAt)
+	return issued, err
+}
+
+// revokeAPIKey revokes a key, reporting sql.ErrNoRows if there is no such
+// active key
+func revokeAPIKey(id int64) error {
+	result, err := db.Exec("UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP WHERE id = ? AND revoked_at IS NULL", id)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// listAPIKeys returns every key, revoked ones included, newest first
+func listAPIKeys() ([]APIKey, error) {
+	rows, err := db.Query(`
+	SELECT id, name, role, prefix, created_at, last_used_at, revoked_at
+	FROM api_keys ORDER BY id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []APIKey{}
+	for rows.Next() {
+		var key APIKey
+		if err := rows.Scan(&key.ID, &key.Name, &key.Role, &key.Prefix,
+			&key.CreatedAt, &key.LastUsedAt, &key.RevokedAt); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// lookupAPIKey finds the active key matching a bearer token and notes that
+// it was used
+func lookupAPIKey(token string) (*Principal, error) {
+	if !strings.HasPrefix(token, apiKeyPrefix) {
+		return nil, errInvalidToken
+	}
+
+	var principal Principal
+	err := db.QueryRow("SELECT id, name, role FROM api_keys WHERE key_hash = ? AND revoked_at IS NULL",
+		hashToken(token)).Scan(&principal.KeyID, &principal.Name, &principal.Role)
+	if err == sql.ErrNoRows {
+		return nil, errInvalidToken
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Only write once a minute so busy keys don't turn every read into a write
+	_, err = db.Exec(`
+	UPDATE api_keys SET last_used_at = CURRENT_TIMESTAMP
+	WHERE id = ? AND (last_used_at IS NULL OR last_used_at < datetime('now', '-1 minute'))`, principal.KeyID)
+	if err != nil {
+		log.Printf("Failed to record API key use: %v", err)
+	}
+	return &principal, nil
+}
+
+// keysRouter dispatches /keys requests
+func keysRouter(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/keys"), "/")
+
+	switch {
+	case id == "" && r.Method == http.MethodGet:
+		listAPIKeysHandler(w, r)
+	case id == "" && r.Method == http.MethodPost:
+		createAPIKeyHandler(w, r)
+	case id == "":
+		w.Header().Set("Allow", "GET, POST")
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+	case r.Method == http.MethodDelete:
+		revokeAPIKeyHandler(w, r)
+	default:
+		w.Header().Set("Allow", "DELETE")
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// listAPIKeysHandler lists issued API keys
+// @Summary List API keys
+// @Description List every issued API key, newest first. The keys themselves are never shown again.
+// @Tags keys
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} APIKey
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /keys [get]
+func listAPIKeysHandler(w http.ResponseWriter, r *http.Request) {
+	keys, err := listAPIKeys()
+	if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Could not fetch API keys")
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, keys)
+}
+
+// createAPIKeyHandler issues a new API key
+// @Summary Issue an API key
+// @Description Issue a new API key with the given role. The key is only included in this response, so store it safely.
+// @Tags keys
+// @Accept multipart/form-data,application/x-www-form-urlencoded
+// @Produce json
+// @Security BearerAuth
+// @Param name formData string true "What the key is for"
+// @Param role formData string true "Role of the key" Enums(viewer, author, editor, admin)
+// @Success 201 {object} APIKey
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /keys [post]
+func createAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(maxFileSize); err != nil && err != http.ErrNotMultipart {
+		writeErrorResponse(w, http.StatusBadRequest, "Failed to parse form data")
+		return
+	}
+
+	name := r.FormValue("name")
+	role := strings.TrimSpace(r.FormValue("role"))
+	if _, ok := roleRank[role]; !ok || strings.TrimSpace(name) == "" {
+		writeErrorResponse(w, http.StatusBadRequest, "name and role (viewer, author, editor or admin) are required")
+		return
+	}
+
+	key, err := issueAPIKey(name, role)
+	if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSONResponse(w, http.StatusCreated, key)
+}
+
+// revokeAPIKeyHandler revokes an API key
+// @Summary Revoke an API key
+// @Description Revoke an API key. Requests made with it are rejected from then on.
+// @Tags keys
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID of the API key"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /keys/{id} [delete]
+func revokeAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, "/keys/"), 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "id must be a number")
+		return
+	}
+
+	if err := revokeAPIKey(id); err == sql.ErrNoRows {
+		writeErrorResponse(w, http.StatusNotFound, "API key not found or already revoked")
+		return
+	} else if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to revoke API key")
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, map[string]string{"message": "API key revoked"})
+}
+
+// runKeysCommand manages API keys from the command line:
+//
+//	blogo keys create -name "CI" -role editor
+//	blogo keys list
+//	blogo keys revoke 3
+func runKeysCommand(args []string) error {
+	usage := fmt.Errorf("usage: keys create -name NAME -role ROLE | keys list | keys revoke ID")
+	if len(args) == 0 {
+		return usage
+	}
+
+	switch args[0] {
+	case "create":
+		flags := flag.NewFlagSet("keys create", flag.ContinueOnError)
+		name := flags.String("name", "", "what the key is for")
+		role := flags.String("role", RoleViewer, "viewer, author, editor or admin")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		key, err := issueAPIKey(*name, *role)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Issued %s key %d for %q. It will not be shown again:\n%s\n", key.Role, key.ID, key.Name, key.Key)
+	case "list":
+		keys, err := listAPIKeys()
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tNAME\tROLE\tPREFIX\tCREATED\tLAST USED\tREVOKED")
+		for _, key := range keys {
+			lastUsed, revoked := "-", "-"
+			if key.LastUsedAt != nil {
+				lastUsed = *key.LastUsedAt
+			}
+			if key.RevokedAt != nil {
+				revoked = *key.RevokedAt
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s…\t%s\t%s\t%s\n",
+				key.ID, key.Name, key.Role, key.Prefix, key.CreatedAt, lastUsed, revoked)
+		}
+		return tw.Flush()
+	case "revoke":
+		if len(args) != 2 {
+			return usage
+		}
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("id must be a number")
+		}
+		if err := revokeAPIKey(id); err == sql.ErrNoRows {
+			return fmt.Errorf("no active API key with id %d", id)
+		} else if err != nil {
+			return err
+		}
+		fmt.Printf("Revoked API key %d\n", id)
+	default:
+		return usage
+	}
+	return nil
+}
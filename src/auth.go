@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Roles, from least to most privileged. Each role can do everything the
+// ones before it can:
+//
+//	viewer  sees drafts, revisions and the trash
+//	author  writes posts and uploads images, and moves them between draft and review
+//	editor  publishes, archives, deletes and restores posts, rolls back revisions and manages tags
+//	admin   purges posts from the trash and manages API keys
+const (
+	RoleViewer = "viewer"
+	RoleAuthor = "author"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// roleRank orders the roles so a higher role satisfies a lower requirement
+var roleRank = map[string]int{
+	RoleViewer: 1,
+	RoleAuthor: 2,
+	RoleEditor: 3,
+	RoleAdmin:  4,
+}
+
+// Principal is the caller a request was authenticated as: an API key, a
+// logged in user, or the ADMIN_TOKEN
+type Principal struct {
+	// ID of the API key used, if any
+	KeyID int64
+
+	// The logged in user and their session, if any
+	UserID    int64
+	SessionID int64
+
+	Name string
+	Role string
+}
+
+type principalKey struct{}
+
+// authenticate resolves the bearer token of a request, which may be an API
+// key or a session's access token. It returns a nil principal for anonymous
+// requests and an error for unknown, expired or revoked tokens.
+// ADMIN_TOKEN remains accepted as an admin credential so the first API
+// keys and users can be created.
+func authenticate(r *http.Request) (*Principal, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return nil, nil
+	}
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok || token == "" {
+		return nil, errInvalidToken
+	}
+
+	if admin := os.Getenv("ADMIN_TOKEN"); admin != "" &&
+		subtle.ConstantTimeCompare([]byte(token), []byte(admin)) == 1 {
+		return &Principal{Name: "ADMIN_TOKEN", Role: RoleAdmin}, nil
+	}
+
+	if strings.HasPrefix(token, apiKeyPrefix) {
+		return lookupAPIKey(token)
+	}
+	return lookupSession(token)
+}
+
+// authMiddleware authenticates the bearer token, if any, and makes the
+// caller available to handlers through principalFrom. Requests with a
+// token that doesn't check out are rejected rather than served anonymously.
+func authMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal, err := authenticate(r)
+		if err != nil {
+			authFailed(r)
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			writeErrorResponse(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		if principal != nil {
+			r = r.WithContext(context.WithValue(r.Context(), principalKey{}, principal))
+		}
+		next(w, r)
+	}
+}
+
+// principalFrom returns the authenticated caller, or nil for anonymous requests
+func principalFrom(r *http.Request) *Principal {
+	principal, _ := r.Context().Value(principalKey{}).(*Principal)
+	return principal
+}
+
+// hasRole reports whether the caller holds at least the given role
+func hasRole(r *http.Request, role string) bool {
+	principal := principalFrom(r)
+	return principal != nil && roleRank[principal.Role] >= roleRank[role]
+}
+
+// isAuthenticated reports whether the request carries a valid token. Any
+// role may see unpublished posts.
+func isAuthenticated(r *http.Request) bool {
+	return hasRole(r, RoleViewer)
+}
+
+// requireRole only lets callers holding at least the given role through,
+// answering 401 to anonymous callers and 403 to those with a lesser role
+func requireRole(role string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if principalFrom(r) == nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeErrorResponse(w, http.StatusUnauthorized, "Authentication required")
+			return
+		}
+		if !hasRole(r, role) {
+			writeErrorResponse(w, http.StatusForbidden, "This action requires the "+role+" role")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// getVisibleBlogPost fetches a blog post by its URL keyword, reporting
+// sql.ErrNoRows when the caller may not see it
+func getVisibleBlogPost(r *http.Request, urlKeyword string) (BlogPost, error) {
+	post, err := getBlogPost(db, urlKeyword)
+	if err == nil && post.Status != StatusPublished && !isAuthenticated(r) {
+		return BlogPost{}, sql.ErrNoRows
+	}
+	return post, err
+}
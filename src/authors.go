@@ -0,0 +1,532 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// maxAuthorLinks caps the number of profile links per author
+const maxAuthorLinks = 10
+
+var (
+	errAuthorNotFound  = errors.New("author not found")
+	errAuthorSlugTaken = errors.New("slug is already used by another author")
+
+	authorSlugPattern = regexp.MustCompile(`^[a-z0-9-]+$`)
+)
+
+// Author is a person who writes blog posts
+// @swagger:model
+type Author struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Slug      string `json:"slug"`
+	Bio       string `json:"bio"`
+	Avatar    string `json:"avatar"`
+	AvatarURL string `json:"avatar_url,omitempty"`
+
+	// Profiles elsewhere, such as social networks or a personal site
+	Links []string `json:"links"`
+
+	CreatedAt string `json:"created_at,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+// AuthorCount is an author together with the number of posts they wrote
+// @swagger:model
+type AuthorCount struct {
+	Author
+	PostCount int `json:"post_count"`
+}
+
+// postAuthorJSON selects a post's author as a JSON object, so posts can be
+// listed with their author without a join
+const postAuthorJSON = `(SELECT json_object(
+		'id', a.id, 'name', a.name, 'slug', a.slug, 'bio', a.bio, 'avatar', a.avatar, 'links', json(a.links)
+	) FROM authors a WHERE a.id = blog_posts.author_id) AS author`
+
+const authorColumns = "id, name, slug, bio, avatar, links, created_at, updated_at"
+
+func scanAuthor(row rowScanner, extra ...interface{}) (Author, error) {
+	var linksJSON string
+	var author Author
+	err := row.Scan(append([]interface{}{
+		&author.ID, &author.Name, &author.Slug, &author.Bio, &author.Avatar, &linksJSON,
+		&author.CreatedAt, &author.UpdatedAt,
+	}, extra...)...)
+	if err != nil {
+		return author, err
+	}
+	if err := json.Unmarshal([]byte(linksJSON), &author.Links); err != nil {
+		fmt.Println("Error unmarshaling author links:", err)
+	}
+	author.finish()
+	return author, nil
+}
+
+// finish fills in the fields derived from the stored ones
+func (a *Author) finish() {
+	if a.Links == nil {
+		a.Links = []string{}
+	}
+	a.AvatarURL = absoluteURL(a.Avatar)
+}
+
+// decodePostAuthor decodes the author selected with postAuthorJSON
+func decodePostAuthor(authorJSON *string) *Author {
+	if authorJSON == nil {
+		return nil
+	}
+	var author Author
+	if err := json.Unmarshal([]byte(*authorJSON), &author); err != nil {
+		fmt.Println("Error unmarshaling author:", err)
+		return nil
+	}
+	author.finish()
+	return &author
+}
+
+func getAuthor(q queryer, slug string) (Author, error) {
+	return scanAuthor(q.QueryRow("SELECT "+authorColumns+" FROM authors WHERE slug = ?", slug))
+}
+
+// resolveAuthorID looks up the ID of the author with the given slug. An
+// empty slug means no author.
+func resolveAuthorID(slug string) (*int64, error) {
+	slug = strings.TrimSpace(slug)
+	if slug == "" {
+		return nil, nil
+	}
+	var id int64
+	err := db.QueryRow("SELECT id FROM authors WHERE slug = ?", slug).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, errAuthorNotFound
+	}
+	return &id, err
+}
+
+// authorPath is the public listing of an author's posts
+func authorPath(slug string) string {
+	return listingPath("author", slug)
+}
+
+// mergeAuthorForm fills form fields missing from a PATCH request with the
+// stored values so that validateAuthor sees a complete author
+func mergeAuthorForm(form url.Values, author Author) {
+	fields := map[string][]string{
+		"name":  {author.Name},
+		"slug":  {author.Slug},
+		"bio":   {author.Bio},
+		"links": author.Links,
+	}
+	for name, value := range fields {
+		if _, ok := form[name]; !ok {
+			form[name] = value
+		}
+	}
+}
+
+// validateAuthor validates the submitted author form. excludeID is the ID of
+// the author being updated, so its own slug is not reported as taken.
+func validateAuthor(r *http.Request, excludeID int64) (Author, error) {
+	var author Author
+
+	author.Name = strings.TrimSpace(r.FormValue("name"))
+	if author.Name == "" || len(author.Name) > 100 {
+		return author, fmt.Errorf("name is required and cannot exceed 100 characters")
+	}
+
+	author.Slug = strings.TrimSpace(r.FormValue("slug"))
+	if !authorSlugPattern.MatchString(author.Slug) || len(author.Slug) > 100 {
+		return author, fmt.Errorf("slug is required and must contain only lowercase letters, numbers, and hyphens")
+	}
+
+	author.Bio = strings.TrimSpace(r.FormValue("bio"))
+	if len(author.Bio) > 1000 {
+		return author, fmt.Errorf("bio cannot exceed 1000 characters")
+	}
+
+	author.Links = []string{}
+	for _, link := range splitQueryList(r.Form["links"]) {
+		u, err := url.Parse(link)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return author, fmt.Errorf("links must be absolute http or https URLs: %s", link)
+		}
+		author.Links = append(author.Links, link)
+	}
+	if len(author.Links) > maxAuthorLinks {
+		return author, fmt.Errorf("an author cannot have more than %d links", maxAuthorLinks)
+	}
+
+	var taken bool
+	err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM authors WHERE slug = ? AND id != ?)", author.Slug, excludeID).Scan(&taken)
+	if err != nil {
+		return author, fmt.Errorf("failed to check slug uniqueness: %v", err)
+	}
+	if taken {
+		return author, errAuthorSlugTaken
+	}
+
+	return author, nil
+}
+
+// saveAvatar stores the uploaded avatar, if any, returning its path or ""
+func saveAvatar(r *http.Request) (string, error) {
+	file, header, err := r.FormFile("avatar")
+	if err == http.ErrMissingFile || err == http.ErrNotMultipart {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	return validateAndSaveFile(r, file, header)
+}
+
+// authorsRouter dispatches /authors requests
+func authorsRouter(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/authors"), "/")
+	slug, action, _ := strings.Cut(path, "/")
+
+	switch {
+	case slug == "" && r.Method == http.MethodGet:
+		listAuthorsHandler(w, r)
+	case slug == "" && r.Method == http.MethodPost:
+		requireRole(RoleEditor, createAuthorHandler)(w, r)
+	case slug == "":
+		w.Header().Set("Allow", "GET, POST")
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+	case action == "posts" && r.Method == http.MethodGet:
+		authorPostsHandler(w, r)
+	case action == "posts":
+		w.Header().Set("Allow", "GET")
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+	case action != "":
+		writeErrorResponse(w, http.StatusNotFound, "Not found")
+	case r.Method == http.MethodGet:
+		authorHandler(w, r)
+	case r.Method == http.MethodPut || r.Method == http.MethodPatch:
+		requireRole(RoleEditor, updateAuthorHandler)(w, r)
+	case r.Method == http.MethodDelete:
+		requireRole(RoleEditor, deleteAuthorHandler)(w, r)
+	default:
+		w.Header().Set("Allow", "GET, PUT, PATCH, DELETE")
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+	}
+}
+
+// authorSlug returns the slug of the author a request is about
+func authorSlug(r *http.Request) string {
+	slug, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/authors/"), "/")
+	return slug
+}
+
+// listAuthorsHandler lists every author
+// @Summary List authors
+// @Description Get every author with the number of posts they wrote, by name.
+// @Description Anonymous callers only see counts of published posts.
+// @Tags authors
+// @Produce json
+// @Success 200 {array} AuthorCount
+// @Failure 500 {object} map[string]string
+// @Router /authors [get]
+func listAuthorsHandler(w http.ResponseWriter, r *http.Request) {
+	visible := "status = 'published'"
+	if isAuthenticated(r) {
+		visible = "1 = 1"
+	}
+
+	rows, err := db.Query(`
+	SELECT ` + authorColumns + `,
+		(SELECT COUNT(*) FROM blog_posts
+		WHERE author_id = authors.id AND deleted_at IS NULL AND ` + visible + `)
+	FROM authors ORDER BY name COLLATE NOCASE, id`)
+	if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Could not fetch authors")
+		return
+	}
+	defer rows.Close()
+
+	authors := []AuthorCount{}
+	for rows.Next() {
+		var entry AuthorCount
+		entry.Author, err = scanAuthor(rows, &entry.PostCount)
+		if err != nil {
+			fmt.Println(err)
+			writeErrorResponse(w, http.StatusInternalServerError, "Could not fetch authors")
+			return
+		}
+		authors = append(authors, entry)
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, authors); err != nil {
+		log.Printf("Failed to write response: %v", err)
+	}
+}
+
+// authorHandler retrieves an author by slug
+// @Summary Get an author
+// @Description Retrieve an author by their slug
+// @Tags authors
+// @Produce json
+// @Param slug path string true "Slug of the author"
+// @Success 200 {object} Author
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /authors/{slug} [get]
+func authorHandler(w http.ResponseWriter, r *http.Request) {
+	author, err := getAuthor(db, authorSlug(r))
+	if err == sql.ErrNoRows {
+		writeErrorResponse(w, http.StatusNotFound, "Author not found")
+		return
+	} else if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, author)
+}
+
+// authorPostsHandler lists the posts of an author
+// @Summary List an author's blog posts
+// @Description Get a paginated list of an author's blog posts. Accepts the same filters, sorting and pagination as /blogs.
+// @Tags authors
+// @Produce json
+// @Param slug path string true "Slug of the author"
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Number of items per page"
+// @Param sort query string false "Comma-separated sort keys, as for /blogs"
+// @Param cursor query string false "Switches to cursor pagination, as for /blogs"
+// @Success 200 {object} PaginatedResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /authors/{slug}/posts [get]
+func authorPostsHandler(w http.ResponseWriter, r *http.Request) {
+	slug := authorSlug(r)
+	if _, err := getAuthor(db, slug); err == sql.ErrNoRows {
+		writeErrorResponse(w, http.StatusNotFound, "Author not found")
+		return
+	} else if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	// This is /blogs?author=slug, so hand over to the listing
+	query := r.URL.Query()
+	query.Set("author", slug)
+	r = r.Clone(r.Context())
+	r.URL.RawQuery = query.Encode()
+	listBlogsHandler(w, r)
+}
+
+// createAuthorHandler creates an author
+// @Summary Create an author
+// @Description Create an author with an optional avatar upload
+// @Tags authors
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param name formData string true "Name"
+// @Param slug formData string true "Slug used in URLs (lowercase letters, numbers and hyphens)"
+// @Param bio formData string false "Short biography"
+// @Param links formData array false "Profile URLs (comma-separated values or multiple fields)"
+// @Param avatar formData file false "Avatar image"
+// @Success 201 {object} Author
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /authors [post]
+func createAuthorHandler(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(maxFileSize); err != nil && err != http.ErrNotMultipart {
+		writeErrorResponse(w, http.StatusBadRequest, "Failed to parse form data")
+		return
+	}
+
+	author, err := validateAuthor(r, 0)
+	if err == errAuthorSlugTaken {
+		writeErrorResponse(w, http.StatusConflict, err.Error())
+		return
+	} else if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if author.Avatar, err = saveAvatar(r); err != nil {
+		writeUploadError(w, err, "Invalid avatar")
+		return
+	}
+
+	links, _ := json.Marshal(author.Links)
+	var created Author
+	err = withTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec("INSERT INTO authors (name, slug, bio, avatar, links) VALUES (?, ?, ?, ?, ?)",
+			author.Name, author.Slug, author.Bio, author.Avatar, string(links))
+		if err != nil {
+			return err
+		}
+		created, err = getAuthor(tx, author.Slug)
+		return err
+	})
+	if err != nil {
+		discardUpload(r, author.Avatar)
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to create author")
+		return
+	}
+
+	if err := writeJSONResponse(w, http.StatusCreated, created); err != nil {
+		log.Printf("Failed to write response: %v", err)
+	}
+}
+
+// updateAuthorHandler replaces or partially updates an author
+// @Summary Update an author
+// @Description Replace (PUT) or partially update (PATCH) an author. PATCH keeps any field that is not sent.
+// @Description The current avatar is kept unless a new one is uploaded.
+// @Tags authors
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param slug path string true "Slug of the author"
+// @Param name formData string false "Name (required for PUT)"
+// @Param slug formData string false "New slug (required for PUT)"
+// @Param bio formData string false "Short biography"
+// @Param links formData array false "Profile URLs (comma-separated values or multiple fields)"
+// @Param avatar formData file false "Replacement avatar image"
+// @Success 200 {object} Author
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /authors/{slug} [put]
+// @Router /authors/{slug} [patch]
+func updateAuthorHandler(w http.ResponseWriter, r *http.Request) {
+	existing, err := getAuthor(db, authorSlug(r))
+	if err == sql.ErrNoRows {
+		writeErrorResponse(w, http.StatusNotFound, "Author not found")
+		return
+	} else if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	if err := r.ParseMultipartForm(maxFileSize); err != nil && err != http.ErrNotMultipart {
+		writeErrorResponse(w, http.StatusBadRequest, "Failed to parse form data")
+		return
+	}
+	if r.Method == http.MethodPatch {
+		mergeAuthorForm(r.Form, existing)
+	}
+
+	author, err := validateAuthor(r, existing.ID)
+	if err == errAuthorSlugTaken {
+		writeErrorResponse(w, http.StatusConflict, err.Error())
+		return
+	} else if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	author.Avatar = existing.Avatar
+	avatar, err := saveAvatar(r)
+	if err != nil {
+		writeUploadError(w, err, "Invalid avatar")
+		return
+	}
+	if avatar != "" {
+		author.Avatar = avatar
+	}
+
+	links, _ := json.Marshal(author.Links)
+	var updated Author
+	err = withTransaction(func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+		UPDATE authors SET name = ?, slug = ?, bio = ?, avatar = ?, links = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`, author.Name, author.Slug, author.Bio, author.Avatar, string(links), existing.ID)
+		if err != nil {
+			return err
+		}
+		updated, err = getAuthor(tx, author.Slug)
+		return err
+	})
+	if err != nil {
+		if author.Avatar != existing.Avatar {
+			discardUpload(r, author.Avatar)
+		}
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to update author")
+		return
+	}
+
+	if updated.Avatar != existing.Avatar {
+		if err := removeUnusedImage(existing.Avatar); err != nil {
+			log.Printf("Failed to remove avatar %s: %v", existing.Avatar, err)
+		}
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, updated); err != nil {
+		log.Printf("Failed to write response: %v", err)
+	}
+}
+
+// deleteAuthorHandler deletes an author
+// @Summary Delete an author
+// @Description Delete an author. Their posts are kept and no longer credited to anyone.
+// @Tags authors
+// @Produce json
+// @Security BearerAuth
+// @Param slug path string true "Slug of the author"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /authors/{slug} [delete]
+func deleteAuthorHandler(w http.ResponseWriter, r *http.Request) {
+	var author Author
+	err := withTransaction(func(tx *sql.Tx) error {
+		var err error
+		if author, err = getAuthor(tx, authorSlug(r)); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("UPDATE blog_posts SET author_id = NULL WHERE author_id = ?", author.ID); err != nil {
+			return err
+		}
+		_, err = tx.Exec("DELETE FROM authors WHERE id = ?", author.ID)
+		return err
+	})
+	if err == sql.ErrNoRows {
+		writeErrorResponse(w, http.StatusNotFound, "Author not found")
+		return
+	} else if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to delete author")
+		return
+	}
+
+	if err := removeUnusedImage(author.Avatar); err != nil {
+		log.Printf("Failed to remove avatar %s: %v", author.Avatar, err)
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, map[string]string{
+		"message": "Author deleted",
+	}); err != nil {
+		log.Printf("Failed to write response: %v", err)
+	}
+}
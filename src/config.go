@@ -0,0 +1,129 @@
+package main
+
+import (
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultBaseURL is used when BASE_URL is not configured, matching the
+// address the server listens on
+const defaultBaseURL = "http://localhost:8080"
+
+// SiteConfig describes the public site the blog is served on. It is read
+// from the environment, so it can live in .env next to the other settings:
+//
+//	BASE_URL=https://example.com
+//	SITE_NAME=Example Blog
+//	SITE_DEFAULT_IMAGE=uploads/default.png
+//	SITE_LOGO=uploads/logo.png
+//	HTML_PAGES=true
+//	TEMPLATES_DIR=/srv/blog/theme
+//
+// Images may be given as paths relative to BASE_URL or as absolute URLs.
+type SiteConfig struct {
+	// Public URL of the site, without a trailing slash
+	BaseURL string
+
+	// Name of the site, used in feed titles and structured data
+	Name string
+
+	// Image used for posts that have none of their own
+	DefaultImage string
+
+	// Logo of the publisher
+	Logo string
+
+	// Whether to serve server-rendered HTML pages under /posts
+	HTMLPages bool
+
+	// Directory of templates overriding the built-in theme
+	TemplatesDir string
+}
+
+// site is the configuration loaded at startup
+var site = SiteConfig{BaseURL: defaultBaseURL, Name: "Blog"}
+
+// loadSiteConfig reads the site configuration from the environment
+func loadSiteConfig() SiteConfig {
+	config := SiteConfig{
+		BaseURL:      strings.TrimRight(os.Getenv("BASE_URL"), "/"),
+		Name:         strings.TrimSpace(os.Getenv("SITE_NAME")),
+		DefaultImage: strings.TrimSpace(os.Getenv("SITE_DEFAULT_IMAGE")),
+		Logo:         strings.TrimSpace(os.Getenv("SITE_LOGO")),
+		TemplatesDir: strings.TrimSpace(os.Getenv("TEMPLATES_DIR")),
+	}
+
+	if value := os.Getenv("HTML_PAGES"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("❌ HTML_PAGES must be true or false, got %q", value)
+		}
+		config.HTMLPages = enabled
+	}
+
+	if config.BaseURL == "" {
+		log.Printf("⚠️ Warning: BASE_URL is not set, generating URLs for %s", defaultBaseURL)
+		config.BaseURL = defaultBaseURL
+	}
+	if u, err := url.Parse(config.BaseURL); err != nil || u.Scheme == "" || u.Host == "" {
+		log.Fatalf("❌ BASE_URL must be an absolute URL such as https://example.com, got %q", config.BaseURL)
+	}
+	if config.Name == "" {
+		config.Name = "Blog"
+	}
+
+	return config
+}
+
+// absoluteURL turns a path on the site, such as /blog/slug or an uploaded
+// image, into an absolute URL. Characters not allowed in URLs, like the
+// spaces in uploaded file names, are escaped while existing escapes, query
+// strings and fragments are kept. Absolute URLs and empty paths are
+// returned as is.
+func absoluteURL(path string) string {
+	if path == "" {
+		return ""
+	}
+	u, err := url.Parse(path)
+	if err == nil && u.IsAbs() {
+		return path
+	}
+	if err != nil {
+		// Not a valid reference, e.g. a file name with a bare %, so treat
+		// all of it as the path
+		u = &url.URL{Path: path}
+	}
+	return site.BaseURL + "/" + strings.TrimPrefix(u.String(), "/")
+}
+
+// durationFromEnv reads a positive duration, warning about and ignoring
+// invalid values
+func durationFromEnv(name string, fallback time.Duration) time.Duration {
+	if raw := os.Getenv(name); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err == nil && d > 0 {
+			return d
+		}
+		log.Printf("⚠️ Warning: invalid %s %q, using %s", name, raw, fallback)
+	}
+	return fallback
+}
+
+// envList reads a comma-separated list, falling back when it is unset
+func envList(name string, fallback []string) []string {
+	raw, ok := os.LookupEnv(name)
+	if !ok {
+		return fallback
+	}
+	list := []string{}
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
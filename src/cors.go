@@ -0,0 +1,216 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultCORSMaxAge is how long browsers may cache a preflight response
+const defaultCORSMaxAge = 10 * time.Minute
+
+// CORSPolicy decides which cross-origin requests browsers may make. It is
+// read from the environment:
+//
+//	CORS_ALLOWED_ORIGINS=https://admin.example.com,https://*.example.com
+//	CORS_ALLOWED_METHODS=GET,POST,PUT,PATCH,DELETE
+//	CORS_ALLOWED_HEADERS=Content-Type,Authorization
+//	CORS_EXPOSED_HEADERS=Retry-After,X-RateLimit-Limit,X-RateLimit-Remaining
+//	CORS_MAX_AGE=10m
+//	CORS_ALLOW_CREDENTIALS=true
+//
+// An origin of "*" allows any site, but browsers refuse credentialed
+// requests to such an API, so credentials are then never allowed.
+type CORSPolicy struct {
+	// Allowed origins, each either exact (https://app.example.com) or
+	// covering every subdomain (https://*.example.com)
+	AllowedOrigins []string
+
+	AllowedMethods []string
+	AllowedHeaders []string
+
+	// Response headers scripts may read besides the CORS-safelisted ones
+	ExposedHeaders []string
+
+	MaxAge time.Duration
+
+	AllowCredentials bool
+
+	// Whether AllowedOrigins contains "*"
+	anyOrigin bool
+}
+
+// loadCORSPolicy reads the CORS policy from the environment. Without
+// CORS_ALLOWED_ORIGINS any origin may read the API, as before, but without
+// credentials.
+func loadCORSPolicy() CORSPolicy {
+	policy := CORSPolicy{
+		AllowedOrigins:   envList("CORS_ALLOWED_ORIGINS", []string{"*"}),
+		AllowedMethods:   envList("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "PATCH", "DELETE"}),
+		AllowedHeaders:   envList("CORS_ALLOWED_HEADERS", []string{"Content-Type", "Authorization"}),
+		ExposedHeaders:   envList("CORS_EXPOSED_HEADERS", []string{"Retry-After", "X-RateLimit-Limit", "X-RateLimit-Remaining"}),
+		MaxAge:           durationFromEnv("CORS_MAX_AGE", defaultCORSMaxAge),
+		AllowCredentials: true,
+	}
+
+	if value := os.Getenv("CORS_ALLOW_CREDENTIALS"); value != "" {
+		allow, err := strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("❌ CORS_ALLOW_CREDENTIALS must be true or false, got %q", value)
+		}
+		policy.AllowCredentials = allow
+	}
+
+	for i, method := range policy.AllowedMethods {
+		policy.AllowedMethods[i] = strings.ToUpper(method)
+	}
+	for _, origin := range policy.AllowedOrigins {
+		if origin == "*" {
+			policy.anyOrigin = true
+			continue
+		}
+		if !validOriginPattern(origin) {
+			log.Fatalf("❌ CORS_ALLOWED_ORIGINS entries must look like https://example.com or https://*.example.com, got %q", origin)
+		}
+	}
+	if policy.anyOrigin && policy.AllowCredentials {
+		if os.Getenv("CORS_ALLOW_CREDENTIALS") != "" {
+			log.Println("⚠️ Warning: credentials cannot be allowed for every origin, list the origins in CORS_ALLOWED_ORIGINS")
+		}
+		policy.AllowCredentials = false
+	}
+
+	return policy
+}
+
+// validOriginPattern reports whether an allowed origin is a bare
+// scheme://host[:port], optionally with a *. wildcard before the host
+func validOriginPattern(pattern string) bool {
+	u, err := url.Parse(strings.Replace(pattern, "://*.", "://wildcard.", 1))
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" &&
+		u.Path == "" && u.RawQuery == "" && u.Fragment == "" && u.User == nil
+}
+
+// allowsOrigin reports whether the Origin of a request is allowed
+func (p CORSPolicy) allowsOrigin(origin string) bool {
+	if p.anyOrigin {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	for _, pattern := range p.AllowedOrigins {
+		pattern = strings.ToLower(pattern)
+		if origin == pattern {
+			return true
+		}
+		// https://*.example.com matches https://a.example.com and
+		// https://a.b.example.com, but not https://example.com itself
+		if prefix, suffix, ok := strings.Cut(pattern, "://*."); ok {
+			host, found := strings.CutPrefix(origin, prefix+"://")
+			if found && strings.HasSuffix(host, "."+suffix) && !strings.ContainsAny(host, "/@") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// allowsMethod reports whether a preflight may go ahead with the requested method
+func (p CORSPolicy) allowsMethod(method string) bool {
+	for _, allowed := range p.AllowedMethods {
+		if allowed == method {
+			return true
+		}
+	}
+	// Browsers don't preflight these, so they are always allowed
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodPost
+}
+
+// allowsHeaders reports whether every header a preflight asks for is allowed
+func (p CORSPolicy) allowsHeaders(requested string) bool {
+	for _, header := range strings.Split(requested, ",") {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			continue
+		}
+		allowed := false
+		for _, name := range p.AllowedHeaders {
+			allowed = allowed || strings.EqualFold(name, header)
+		}
+		if !allowed {
+			return false
+		}
+	}
+	return true
+}
+
+// Handler applies the policy to requests before passing them on. Preflight
+// requests are answered here: 204 when allowed, 403 when not.
+func (p CORSPolicy) Handler(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Responses differ by origin unless every origin is treated alike,
+		// so caches must keep them apart
+		if !p.anyOrigin {
+			w.Header().Add("Vary", "Origin")
+		}
+
+		origin := r.Header.Get("Origin")
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			p.preflight(w, r, origin)
+			return
+		}
+
+		if origin != "" && p.allowsOrigin(origin) {
+			p.allowOrigin(w, origin)
+			if len(p.ExposedHeaders) > 0 {
+				w.Header().Set("Access-Control-Expose-Headers", strings.Join(p.ExposedHeaders, ", "))
+			}
+		}
+		if r.Method == http.MethodOptions {
+			w.Header().Set("Allow", strings.Join(append([]string{http.MethodOptions}, p.AllowedMethods...), ", "))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// allowOrigin sets the headers letting the origin read the response
+func (p CORSPolicy) allowOrigin(w http.ResponseWriter, origin string) {
+	if p.anyOrigin {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+	} else {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+	}
+	if p.AllowCredentials {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// preflight answers a preflight request, allowing it only when the origin,
+// method and every requested header are allowed
+func (p CORSPolicy) preflight(w http.ResponseWriter, r *http.Request, origin string) {
+	w.Header().Add("Vary", "Access-Control-Request-Method")
+	w.Header().Add("Vary", "Access-Control-Request-Headers")
+
+	if origin == "" || !p.allowsOrigin(origin) {
+		writeErrorResponse(w, http.StatusForbidden, "Origin not allowed")
+		return
+	}
+	if !p.allowsMethod(r.Header.Get("Access-Control-Request-Method")) ||
+		!p.allowsHeaders(r.Header.Get("Access-Control-Request-Headers")) {
+		writeErrorResponse(w, http.StatusForbidden, "Method or headers not allowed")
+		return
+	}
+
+	p.allowOrigin(w, origin)
+	w.Header().Set("Access-Control-Allow-Methods", strings.Join(p.AllowedMethods, ", "))
+	if len(p.AllowedHeaders) > 0 {
+		w.Header().Set("Access-Control-Allow-Headers", strings.Join(p.AllowedHeaders, ", "))
+	}
+	w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(p.MaxAge.Seconds())))
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CursorResponse represents a page of blog posts fetched with a cursor
+// @swagger:model
+type CursorResponse struct {
+	// List of blog posts
+	Posts []BlogPost `json:"posts"`
+
+	// Number of items per page
+	PageSize int `json:"pageSize"`
+
+	// Cursor for the following page, absent on the last page
+	NextCursor *string `json:"next_cursor,omitempty"`
+
+	// Cursor for the preceding page, absent on the first page
+	PrevCursor *string `json:"prev_cursor,omitempty"`
+
+	// Total number of matching posts, only present when count=true
+	TotalPosts *int `json:"totalPosts,omitempty"`
+}
+
+// listCursor is the decoded form of an opaque pagination cursor. It holds
+// the sort it was issued for and the sort key values (id last) of the row
+// to continue from.
+type listCursor struct {
+	Sort   string        `json:"s"`
+	Values []interface{} `json:"v"`
+
+	// Whether the cursor pages backwards from the row
+	Before bool `json:"b,omitempty"`
+}
+
+func encodeCursor(c listCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(raw string) (listCursor, error) {
+	var c listCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return c, fmt.Errorf("invalid cursor")
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&c); err != nil {
+		return c, fmt.Errorf("invalid cursor")
+	}
+
+	// Numbers must be bound as numbers, or SQLite compares them as text
+	for i, value := range c.Values {
+		if number, ok := value.(json.Number); ok {
+			if n, err := number.Int64(); err == nil {
+				c.Values[i] = n
+			} else if f, err := number.Float64(); err == nil {
+				c.Values[i] = f
+			} else {
+				return c, fmt.Errorf("invalid cursor")
+			}
+		}
+	}
+	return c, nil
+}
+
+// keysetCondition builds the WHERE condition selecting rows strictly after
+// (or, with before, strictly ahead of) the given sort key values:
+//
+//	(k1 > v1) OR (k1 = v1 AND k2 > v2) OR ...
+func keysetCondition(keys []sortKey, values []interface{}, before bool) (string, []interface{}) {
+	var terms []string
+	var args []interface{}
+
+	for i, key := range keys {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, keys[j].Expr+" = ?")
+			args = append(args, values[j])
+		}
+
+		op := ">"
+		if key.Desc != before {
+			op = "<"
+		}
+		parts = append(parts, key.Expr+" "+op+" ?")
+		args = append(args, values[i])
+
+		terms = append(terms, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	return "(" + strings.Join(terms, " OR ") + ")", args
+}
+
+// reverseKeys flips the direction of every sort key, for paging backwards
+func reverseKeys(keys []sortKey) []sortKey {
+	reversed := make([]sortKey, len(keys))
+	for i, key := range keys {
+		key.Desc = !key.Desc
+		reversed[i] = key
+	}
+	return reversed
+}
+
+// listBlogsByCursor serves /blogs with keyset pagination. It never uses
+// OFFSET, so pages stay cheap deep into the archive and don't skip or repeat
+// posts when new ones are inserted between requests.
+func listBlogsByCursor(w http.ResponseWriter, r *http.Request, where string, args []interface{}, pageSize int) {
+	var cursor listCursor
+	var err error
+	if raw := r.URL.Query().Get("cursor"); raw != "" {
+		if cursor, err = decodeCursor(raw); err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	sortParam := r.URL.Query().Get("sort")
+	if sortParam == "" {
+		sortParam = cursor.Sort
+	}
+	keys, err := parseSort(sortParam)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	sort := sortString(keys)
+	if cursor.Values != nil && (cursor.Sort != sort || len(cursor.Values) != len(keys)) {
+		writeErrorResponse(w, http.StatusBadRequest, "cursor does not match the requested sort")
+		return
+	}
+
+	var totalPosts *int
+	if count, _ := strconv.ParseBool(r.URL.Query().Get("count")); count {
+		var total int
+		if err := db.QueryRow("SELECT COUNT(*) FROM blog_posts WHERE "+where, args...).Scan(&total); err != nil {
+			writeErrorResponse(w, http.StatusInternalServerError, "Could not count blog posts")
+			return
+		}
+		totalPosts = &total
+	}
+
+	queryArgs := append([]interface{}{}, args...)
+	if cursor.Values != nil {
+		condition, keysetArgs := keysetCondition(keys, cursor.Values, cursor.Before)
+		where += " AND " + condition
+		queryArgs = append(queryArgs, keysetArgs...)
+	}
+
+	// Paging backwards reads the rows in reverse, then flips them back
+	order := keys
+	if cursor.Before {
+		order = reverseKeys(keys)
+	}
+
+	exprs := make([]string, len(keys))
+	for i, key := range keys {
+		exprs[i] = key.Expr
+	}
+
+	// Fetch one extra row to learn whether another page follows
+	rows, err := db.Query("SELECT "+blogPostColumns+", "+strings.Join(exprs, ", ")+
+		" FROM blog_posts WHERE "+where+" ORDER BY "+orderByClause(order)+" LIMIT ?",
+		append(queryArgs, pageSize+1)...)
+	if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Could not fetch blog posts")
+		return
+	}
+	defer rows.Close()
+
+	var posts []BlogPost
+	var values [][]interface{}
+	for rows.Next() {
+		rowValues := make([]interface{}, len(keys))
+		dest := make([]interface{}, len(keys))
+		for i := range rowValues {
+			dest[i] = &rowValues[i]
+		}
+		post, err := scanBlogPost(rows, dest...)
+		if err != nil {
+			continue
+		}
+		// The driver parses DATETIME columns; put them back in the stored
+		// format so the cursor compares against them correctly
+		for i, value := range rowValues {
+			if t, ok := value.(time.Time); ok {
+				rowValues[i] = t.UTC().Format(sqliteTimeFormat)
+			}
+		}
+		posts = append(posts, post)
+		values = append(values, rowValues)
+	}
+
+	more := len(posts) > pageSize
+	if more {
+		posts = posts[:pageSize]
+		values = values[:pageSize]
+	}
+	if cursor.Before {
+		for i, j := 0, len(posts)-1; i < j; i, j = i+1, j-1 {
+			posts[i], posts[j] = posts[j], posts[i]
+			values[i], values[j] = values[j], values[i]
+		}
+	}
+
+	response := CursorResponse{
+		Posts:      posts,
+		PageSize:   pageSize,
+		TotalPosts: totalPosts,
+	}
+	if len(posts) > 0 {
+		// Going forwards there is a previous page whenever we started from a
+		// cursor; going backwards, only if the extra row turned up
+		hasNext := more || cursor.Before
+		hasPrev := cursor.Values != nil && !cursor.Before || cursor.Before && more
+
+		if hasNext {
+			next := encodeCursor(listCursor{Sort: sort, Values: values[len(values)-1]})
+			response.NextCursor = &next
+		}
+		if hasPrev {
+			prev := encodeCursor(listCursor{Sort: sort, Values: values[0], Before: true})
+			response.PrevCursor = &prev
+		}
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []listCursor{
+		{Sort: "-created_at", Values: []interface{}{"2024-05-01 10:00:00", int64(42)}},
+		{Sort: "priority,-id", Values: []interface{}{int64(3), int64(7)}, Before: true},
+		{Sort: "title", Values: []interface{}{"Ünïcode & \"quotes\"", int64(1)}},
+		{Sort: "score", Values: []interface{}{1.5, int64(9)}},
+		{Sort: "-id", Values: []interface{}{int64(9007199254740993)}},
+		{Sort: "published_at", Values: []interface{}{nil, int64(2)}},
+	}
+
+	for _, want := range tests {
+		raw := encodeCursor(want)
+		got, err := decodeCursor(raw)
+		if err != nil {
+			t.Errorf("decodeCursor(encodeCursor(%+v)) failed: %v", want, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("cursor round trip = %+v, want %+v", got, want)
+		}
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{"not base64", "!!!"},
+		{"padded base64", "e30="},
+		{"not json", encodeRaw("not json")},
+		{"wrong shape", encodeRaw(`{"s": 1}`)},
+		{"number out of range", encodeRaw(`{"s":"id","v":[1e999]}`)},
+	}
+
+	for _, tt := range tests {
+		if _, err := decodeCursor(tt.raw); err == nil {
+			t.Errorf("%s: decodeCursor(%q) succeeded, want an error", tt.name, tt.raw)
+		}
+	}
+}
+
+func TestKeysetCondition(t *testing.T) {
+	keys := []sortKey{{Name: "priority", Expr: "priority", Desc: true}, {Name: "id", Expr: "id"}}
+	tests := []struct {
+		before    bool
+		condition string
+	}{
+		{false, "((priority < ?) OR (priority = ? AND id > ?))"},
+		{true, "((priority > ?) OR (priority = ? AND id < ?))"},
+	}
+
+	for _, tt := range tests {
+		condition, args := keysetCondition(keys, []interface{}{int64(3), int64(7)}, tt.before)
+		if condition != tt.condition {
+			t.Errorf("keysetCondition(before=%v) = %q, want %q", tt.before, condition, tt.condition)
+		}
+		if want := []interface{}{int64(3), int64(3), int64(7)}; !reflect.DeepEqual(args, want) {
+			t.Errorf("keysetCondition(before=%v) args = %v, want %v", tt.before, args, want)
+		}
+	}
+}
+
+// encodeRaw wraps arbitrary text the way encodeCursor wraps its JSON
+func encodeRaw(text string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(text))
+}
@@ -0,0 +1,430 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// feedSize is the number of most recent posts included in a feed
+const feedSize = 20
+
+// RSS represents an RSS 2.0 document
+// @swagger:model
+type RSS struct {
+	XMLName xml.Name   `xml:"rss" json:"-"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel RSSChannel `xml:"channel"`
+}
+
+// RSSChannel is the channel of an RSS feed
+// @swagger:model
+type RSSChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	SelfLink      AtomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []RSSItem `xml:"item"`
+}
+
+// RSSItem is a single post in an RSS feed
+// @swagger:model
+type RSSItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	Description string        `xml:"description,omitempty"`
+	GUID        RSSGUID       `xml:"guid"`
+	PubDate     string        `xml:"pubDate"`
+	Categories  []string      `xml:"category"`
+	Enclosure   *RSSEnclosure `xml:"enclosure,omitempty"`
+}
+
+// RSSGUID uniquely identifies an RSS item
+type RSSGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// RSSEnclosure attaches a post's image to an RSS item
+type RSSEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int64  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// AtomFeed represents an Atom 1.0 document
+// @swagger:model
+type AtomFeed struct {
+	XMLName xml.Name    `xml:"feed" json:"-"`
+	XMLNS   string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  AtomAuthor  `xml:"author"`
+	Links   []AtomLink  `xml:"link"`
+	Entries []AtomEntry `xml:"entry"`
+}
+
+// AtomEntry is a single post in an Atom feed
+// @swagger:model
+type AtomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Summary    string         `xml:"summary,omitempty"`
+	Author     *AtomAuthor    `xml:"author"`
+	Links      []AtomLink     `xml:"link"`
+	Categories []AtomCategory `xml:"category"`
+}
+
+// AtomLink is an Atom link element, also used for RSS self links
+type AtomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr,omitempty"`
+	Type   string `xml:"type,attr,omitempty"`
+	Length int64  `xml:"length,attr,omitempty"`
+}
+
+// AtomAuthor names the author of an Atom feed or entry
+type AtomAuthor struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+// AtomCategory is a tag on an Atom entry
+type AtomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// JSONFeed represents a JSON Feed 1.1 document
+// @swagger:model
+type JSONFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Icon        string         `json:"icon,omitempty"`
+	Items       []JSONFeedItem `json:"items"`
+}
+
+// JSONFeedItem is a single post in a JSON Feed
+// @swagger:model
+type JSONFeedItem struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url"`
+	Title         string   `json:"title"`
+	ContentHTML   string   `json:"content_html"`
+	Summary       string   `json:"summary,omitempty"`
+	Image         string   `json:"image,omitempty"`
+	DatePublished string   `json:"date_published"`
+	DateModified  string   `json:"date_modified"`
+	Tags          []string `json:"tags,omitempty"`
+
+	Authors []JSONFeedAuthor `json:"authors,omitempty"`
+}
+
+// JSONFeedAuthor is the author of a post in a JSON Feed
+type JSONFeedAuthor struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Avatar string `json:"avatar,omitempty"`
+}
+
+// feedVariant describes which posts a feed covers
+type feedVariant struct {
+	// Title suffix, e.g. "Tag: kubernetes"
+	Label string
+
+	// Path of the feed without its extension, e.g. /feeds/tag/kubernetes
+	Path string
+
+	filter postFilter
+}
+
+// parseFeedRequest works out the format and variant of a feed request.
+// Feeds live at /feed.{rss,atom,json} and /feeds/{tag,topic,industry,author}/{value}.{rss,atom,json},
+// and accept the same filter query parameters as /blogs.
+func parseFeedRequest(r *http.Request) (format string, variant feedVariant, err error) {
+	path := r.URL.Path
+	ext := filepath.Ext(path)
+	format = strings.TrimPrefix(ext, ".")
+	variant.Path = strings.TrimSuffix(path, ext)
+
+	variant.filter, err = parsePostFilter(r)
+	if err != nil {
+		return format, variant, err
+	}
+	// Feeds are public, so never include unpublished posts
+	variant.filter.authenticated = false
+	variant.filter.Status = ""
+
+	if rest, ok := strings.CutPrefix(variant.Path, "/feeds/"); ok {
+		kind, rawValue, _ := strings.Cut(rest, "/")
+		value, err := url.PathUnescape(rawValue)
+		if err != nil || value == "" || strings.Contains(value, "/") {
+			return format, variant, fmt.Errorf("invalid feed path")
+		}
+
+		switch kind {
+		case "tag":
+			variant.Label = "Tag: " + value
+			variant.filter.Tags = []string{value}
+			variant.filter.MatchAll = false
+		case "topic":
+			variant.Label = "Topic: " + value
+			variant.filter.Topic = value
+		case "industry":
+			variant.Label = "Industry: " + value
+			variant.filter.Industry = value
+		case "author":
+			variant.Label = "Author: " + value
+			variant.filter.Author = value
+		default:
+			return format, variant, fmt.Errorf("unknown feed type %q: must be tag, topic, industry or author", kind)
+		}
+	}
+
+	return format, variant, nil
+}
+
+// fetchFeedPosts returns the most recent published posts of a feed variant
+func fetchFeedPosts(variant feedVariant) ([]BlogPost, error) {
+	where, args := variant.filter.where()
+	rows, err := db.Query("SELECT "+blogPostColumns+" FROM blog_posts WHERE "+where+
+		" ORDER BY created_at DESC, id DESC LIMIT ?", append(args, feedSize)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []BlogPost
+	for rows.Next() {
+		post, err := scanBlogPost(rows)
+		if err != nil {
+			continue
+		}
+		posts = append(posts, post)
+	}
+	return posts, rows.Err()
+}
+
+// parseDBTime parses a DATETIME column as scanned from the database
+func parseDBTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return t.UTC()
+}
+
+// imageEnclosure describes a post's uploaded image, or returns nil when the
+// post has none or the file is missing
+func imageEnclosure(image string) *RSSEnclosure {
+	if image == "" {
+		return nil
+	}
+	info, err := os.Stat(image)
+	if err != nil {
+		return nil
+	}
+	return &RSSEnclosure{
+		URL:    absoluteURL(filepath.ToSlash(image)),
+		Length: info.Size(),
+		Type:   mime.TypeByExtension(filepath.Ext(image)),
+	}
+}
+
+// feedMeta holds what every feed format needs to describe itself
+type feedMeta struct {
+	Title   string
+	Path    string
+	Updated time.Time
+}
+
+// rssFeed builds an RSS 2.0 document
+func rssFeed(meta feedMeta, posts []BlogPost) RSS {
+	channel := RSSChannel{
+		Title:       meta.Title,
+		Link:        absoluteURL("/"),
+		Description: "Latest posts",
+		SelfLink:    AtomLink{Href: absoluteURL(meta.Path) + ".rss", Rel: "self", Type: "application/rss+xml"},
+		Items:       []RSSItem{},
+	}
+	if !meta.Updated.IsZero() {
+		channel.LastBuildDate = meta.Updated.Format(time.RFC1123Z)
+	}
+
+	for _, post := range posts {
+		link := postURL(post.UrlKeyword)
+		channel.Items = append(channel.Items, RSSItem{
+			Title:       post.Title,
+			Link:        link,
+			Description: post.MetaDescription,
+			GUID:        RSSGUID{Value: link, IsPermaLink: true},
+			PubDate:     parseDBTime(post.CreatedAt).Format(time.RFC1123Z),
+			Categories:  post.Tags,
+			Enclosure:   imageEnclosure(post.Image),
+		})
+	}
+	return RSS{Version: "2.0", AtomNS: "http://www.w3.org/2005/Atom", Channel: channel}
+}
+
+// atomFeed builds an Atom 1.0 document
+func atomFeed(meta feedMeta, posts []BlogPost) AtomFeed {
+	feed := AtomFeed{
+		XMLNS:   "http://www.w3.org/2005/Atom",
+		ID:      absoluteURL(meta.Path) + ".atom",
+		Title:   meta.Title,
+		Updated: meta.Updated.Format(time.RFC3339),
+		Author:  AtomAuthor{Name: site.Name},
+		Links: []AtomLink{
+			{Href: absoluteURL(meta.Path) + ".atom", Rel: "self", Type: "application/atom+xml"},
+			{Href: absoluteURL("/"), Rel: "alternate"},
+		},
+		Entries: []AtomEntry{},
+	}
+
+	for _, post := range posts {
+		link := postURL(post.UrlKeyword)
+		entry := AtomEntry{
+			ID:        link,
+			Title:     post.Title,
+			Published: parseDBTime(post.CreatedAt).Format(time.RFC3339),
+			Updated:   parseDBTime(post.UpdatedAt).Format(time.RFC3339),
+			Summary:   post.MetaDescription,
+			Links:     []AtomLink{{Href: link, Rel: "alternate"}},
+		}
+		if post.Author != nil {
+			entry.Author = &AtomAuthor{Name: post.Author.Name, URI: absoluteURL(authorPath(post.Author.Slug))}
+		}
+		if enclosure := imageEnclosure(post.Image); enclosure != nil {
+			entry.Links = append(entry.Links, AtomLink{
+				Href: enclosure.URL, Rel: "enclosure", Type: enclosure.Type, Length: enclosure.Length,
+			})
+		}
+		for _, tag := range post.Tags {
+			entry.Categories = append(entry.Categories, AtomCategory{Term: tag})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	return feed
+}
+
+// jsonFeed builds a JSON Feed 1.1 document
+func jsonFeed(meta feedMeta, posts []BlogPost) JSONFeed {
+	feed := JSONFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       meta.Title,
+		HomePageURL: absoluteURL("/"),
+		FeedURL:     absoluteURL(meta.Path + ".json"),
+		Icon:        absoluteURL(site.Logo),
+		Items:       []JSONFeedItem{},
+	}
+
+	for _, post := range posts {
+		link := postURL(post.UrlKeyword)
+		item := JSONFeedItem{
+			ID:            link,
+			URL:           link,
+			Title:         post.Title,
+			ContentHTML:   post.DescriptionHTML,
+			Summary:       post.MetaDescription,
+			DatePublished: parseDBTime(post.CreatedAt).Format(time.RFC3339),
+			DateModified:  parseDBTime(post.UpdatedAt).Format(time.RFC3339),
+			Tags:          post.Tags,
+			Image:         post.ImageURL,
+		}
+		if post.Author != nil {
+			item.Authors = []JSONFeedAuthor{{
+				Name:   post.Author.Name,
+				URL:    absoluteURL(authorPath(post.Author.Slug)),
+				Avatar: post.Author.AvatarURL,
+			}}
+		}
+		feed.Items = append(feed.Items, item)
+	}
+	return feed
+}
+
+// feedHandler serves RSS 2.0, Atom and JSON feeds of published posts
+// @Summary RSS, Atom and JSON feeds
+// @Description Get the most recent published posts as RSS 2.0 (/feed.rss), Atom (/feed.atom) or JSON Feed 1.1 (/feed.json).
+// @Description Per-tag, per-topic, per-industry and per-author variants live at /feeds/{tag,topic,industry,author}/{value}.{rss,atom,json},
+// @Description and the /blogs filter parameters are accepted as well.
+// @Tags feeds
+// @Produce xml,json
+// @Param tags query string false "Comma-separated tags to filter by"
+// @Param tags_match query string false "Whether posts must have any or all of the tags (any, all)"
+// @Param topic query string false "Topic"
+// @Param service query string false "Service"
+// @Param industry query string false "Industry"
+// @Param author query string false "Slug of the author"
+// @Param priority query string false "Comma-separated priorities"
+// @Param created_from query string false "Only posts created on or after this date (YYYY-MM-DD or RFC 3339)"
+// @Param created_to query string false "Only posts created on or before this date (YYYY-MM-DD or RFC 3339)"
+// @Success 200 {object} JSONFeed
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /feed.rss [get]
+// @Router /feed.atom [get]
+// @Router /feed.json [get]
+func feedHandler(w http.ResponseWriter, r *http.Request) {
+	format, variant, err := parseFeedRequest(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if format != "rss" && format != "atom" && format != "json" {
+		writeErrorResponse(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	posts, err := fetchFeedPosts(variant)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Could not generate feed", http.StatusInternalServerError)
+		return
+	}
+
+	meta := feedMeta{Title: site.Name, Path: variant.Path}
+	if variant.Label != "" {
+		meta.Title += " — " + variant.Label
+	}
+	for _, post := range posts {
+		if updated := parseDBTime(post.UpdatedAt); updated.After(meta.Updated) {
+			meta.Updated = updated
+		}
+	}
+
+	var document interface{}
+	switch format {
+	case "json":
+		w.Header().Set("Content-Type", "application/feed+json; charset=utf-8")
+		if err := json.NewEncoder(w).Encode(jsonFeed(meta, posts)); err != nil {
+			log.Printf("Failed to write feed: %v", err)
+		}
+		return
+	case "atom":
+		w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+		document = atomFeed(meta, posts)
+	default:
+		w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+		document = rssFeed(meta, posts)
+	}
+
+	w.Write([]byte(xml.Header))
+	if err := xml.NewEncoder(w).Encode(document); err != nil {
+		log.Printf("Failed to write feed: %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// postFilter holds the listing filters accepted as query parameters
+type postFilter struct {
+	// Whether drafts and other unpublished posts may be listed
+	authenticated bool
+
+	Status      string
+	Tags        []string
+	MatchAll    bool
+	Topic       string
+	Service     string
+	Industry    string
+	Author      string
+	Priorities  []string
+	CreatedFrom string
+	CreatedTo   string
+}
+
+// parsePostFilter reads the listing filters from the query string:
+//
+//	tags=a,b&tags_match=any|all, topic, service, industry, author (slug),
+//	priority=high,maximum, created_from and created_to (YYYY-MM-DD or RFC 3339)
+//
+// and status, which is only honoured for authenticated callers.
+func parsePostFilter(r *http.Request) (postFilter, error) {
+	q := r.URL.Query()
+	filter := postFilter{
+		authenticated: isAuthenticated(r),
+		Topic:         strings.TrimSpace(q.Get("topic")),
+		Service:       strings.TrimSpace(q.Get("service")),
+		Industry:      strings.TrimSpace(q.Get("industry")),
+		Author:        strings.TrimSpace(q.Get("author")),
+	}
+
+	if status := q.Get("status"); status != "" && filter.authenticated {
+		if _, ok := statusTransitions[status]; !ok {
+			return filter, fmt.Errorf("invalid status value")
+		}
+		filter.Status = status
+	}
+
+	// Tag names are case-insensitive, so drop case-only duplicates too
+	for _, tag := range splitQueryList(q["tags"]) {
+		duplicate := false
+		for _, seen := range filter.Tags {
+			duplicate = duplicate || strings.EqualFold(seen, tag)
+		}
+		if !duplicate {
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+	switch q.Get("tags_match") {
+	case "", "any":
+	case "all":
+		filter.MatchAll = true
+	default:
+		return filter, fmt.Errorf("tags_match must be any or all")
+	}
+
+	filter.Priorities = splitQueryList(q["priority"])
+	for _, priority := range filter.Priorities {
+		if _, ok := PriorityWeight[priority]; !ok {
+			return filter, fmt.Errorf("invalid priority value: must be maximum, high, or normal")
+		}
+	}
+
+	var err error
+	if filter.CreatedFrom, err = parseDateBound(q.Get("created_from"), false); err != nil {
+		return filter, fmt.Errorf("created_from: %v", err)
+	}
+	if filter.CreatedTo, err = parseDateBound(q.Get("created_to"), true); err != nil {
+		return filter, fmt.Errorf("created_to: %v", err)
+	}
+
+	return filter, nil
+}
+
+// splitQueryList flattens repeated and comma-separated query values,
+// dropping blanks and duplicates
+func splitQueryList(values []string) []string {
+	seen := map[string]bool{}
+	var list []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+			if item != "" && !seen[item] {
+				seen[item] = true
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
+
+// parseDateBound converts a YYYY-MM-DD or RFC 3339 value into the format
+// stored in created_at. A date-only upper bound includes that whole day,
+// so it is returned as the start of the following day and compared with <.
+func parseDateBound(value string, upper bool) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		if upper {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t.Format(sqliteTimeFormat), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return "", fmt.Errorf("must be a date (YYYY-MM-DD) or RFC 3339 timestamp")
+	}
+	if upper {
+		// Make the bound inclusive of the given second
+		t = t.Add(time.Second)
+	}
+	return t.UTC().Format(sqliteTimeFormat), nil
+}
+
+// where builds the SQL condition and arguments selecting the filtered posts
+func (f postFilter) where() (string, []interface{}) {
+	conditions := []string{"deleted_at IS NULL"}
+	var args []interface{}
+
+	if !f.authenticated {
+		conditions = append(conditions, "status = 'published'")
+	} else if f.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, f.Status)
+	}
+
+	if len(f.Tags) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(f.Tags)), ",")
+		if f.MatchAll {
+			conditions = append(conditions, fmt.Sprintf(`(SELECT COUNT(*) FROM post_tags pt
+				JOIN tags t ON t.id = pt.tag_id
+				WHERE pt.post_id = blog_posts.id AND t.name IN (%s)) = %d`,
+				placeholders, len(f.Tags)))
+		} else {
+			conditions = append(conditions, fmt.Sprintf(`EXISTS (SELECT 1 FROM post_tags pt
+				JOIN tags t ON t.id = pt.tag_id
+				WHERE pt.post_id = blog_posts.id AND t.name IN (%s))`, placeholders))
+		}
+		for _, tag := range f.Tags {
+			args = append(args, tag)
+		}
+	}
+
+	for _, field := range []struct{ column, value string }{
+		{"topic", f.Topic},
+		{"service", f.Service},
+		{"industry", f.Industry},
+	} {
+		if field.value != "" {
+			conditions = append(conditions, field.column+" = ?")
+			args = append(args, field.value)
+		}
+	}
+
+	if f.Author != "" {
+		conditions = append(conditions, "author_id = (SELECT id FROM authors WHERE slug = ?)")
+		args = append(args, f.Author)
+	}
+
+	if len(f.Priorities) > 0 {
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(f.Priorities)), ",")
+		conditions = append(conditions, "priority IN ("+placeholders+")")
+		for _, priority := range f.Priorities {
+			args = append(args, priority)
+		}
+	}
+
+	if f.CreatedFrom != "" {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, f.CreatedFrom)
+	}
+	if f.CreatedTo != "" {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, f.CreatedTo)
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
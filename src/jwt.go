@@ -0,0 +1,122 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+// Access tokens are JSON Web Tokens signed with HMAC-SHA256. They are short
+// lived; the admin frontend keeps the session going with refresh tokens.
+const (
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+
+	// minJWTSecretLength is the shortest JWT_SECRET accepted, as recommended
+	// for HS256 keys
+	minJWTSecretLength = 32
+)
+
+var errExpiredToken = errors.New("token expired")
+
+var (
+	jwtSecret       []byte
+	accessTokenTTL  = defaultAccessTokenTTL
+	refreshTokenTTL = defaultRefreshTokenTTL
+)
+
+// jwtHeader is the only header blogo issues and accepts. Pinning it rules
+// out "alg": "none" and algorithm confusion.
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// AccessClaims are the claims of an access token
+type AccessClaims struct {
+	Issuer    string `json:"iss"`
+	Subject   string `json:"sub"`
+	SessionID int64  `json:"sid"`
+	Role      string `json:"role"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// loadJWTConfig reads the signing secret and token lifetimes from the
+// environment:
+//
+//	JWT_SECRET=<at least 32 random characters>
+//	ACCESS_TOKEN_TTL=15m
+//	REFRESH_TOKEN_TTL=720h
+//
+// Without JWT_SECRET a random secret is generated, so logins don't survive
+// a restart.
+func loadJWTConfig() {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		log.Println("⚠️ Warning: JWT_SECRET is not set, sessions will end when the server restarts")
+		jwtSecret = make([]byte, 32)
+		if _, err := rand.Read(jwtSecret); err != nil {
+			log.Fatal("❌ Failed to generate a JWT secret:", err)
+		}
+	} else if len(secret) < minJWTSecretLength {
+		log.Fatalf("❌ JWT_SECRET must be at least %d characters long", minJWTSecretLength)
+	} else {
+		jwtSecret = []byte(secret)
+	}
+
+	accessTokenTTL = durationFromEnv("ACCESS_TOKEN_TTL", defaultAccessTokenTTL)
+	refreshTokenTTL = durationFromEnv("REFRESH_TOKEN_TTL", defaultRefreshTokenTTL)
+}
+
+// jwtSignature signs the header and payload of a token
+func jwtSignature(signingInput string) string {
+	mac := hmac.New(sha256.New, jwtSecret)
+	mac.Write([]byte(signingInput))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// signAccessToken encodes and signs the claims as a JWT
+func signAccessToken(claims AccessClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signingInput + "." + jwtSignature(signingInput), nil
+}
+
+// parseAccessToken verifies the signature, issuer and expiry of a JWT and
+// returns its claims
+func parseAccessToken(token string) (AccessClaims, error) {
+	var claims AccessClaims
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != jwtHeader {
+		return claims, errInvalidToken
+	}
+	signature := jwtSignature(parts[0] + "." + parts[1])
+	if !hmac.Equal([]byte(parts[2]), []byte(signature)) {
+		return claims, errInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return claims, errInvalidToken
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return claims, errInvalidToken
+	}
+
+	if claims.Issuer != site.BaseURL {
+		return claims, errInvalidToken
+	}
+	if time.Now().Unix() >= claims.ExpiresAt {
+		return claims, errExpiredToken
+	}
+	return claims, nil
+}
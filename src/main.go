@@ -1,14 +1,16 @@
 package main
 
 import (
+	"crypto/rand"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
-	"encoding/xml"
 	"fmt"
 	"io"
 	"log"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -27,6 +29,10 @@ import (
 // @description API for managing blog posts.
 // @BasePath /
 
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+
 // BlogPost represents a blog post with metadata
 // @swagger:model
 type BlogPost struct {
@@ -36,58 +42,21 @@ type BlogPost struct {
 	FocusKeyword    string   `json:"focus_keyword"`
 	UrlKeyword      string   `json:"url_keyword"`
 	Image           string   `json:"image"`
+	ImageURL        string   `json:"image_url,omitempty"`
 	Tags            []string `json:"tags"`
 	Topic           string   `json:"topic"`
 	Service         string   `json:"service"`
 	Industry        string   `json:"industry"`
 	Priority        string   `json:"priority" enums:"maximum,high,normal"`
-	Description     string   `json:"description"`
+	Status          string   `json:"status" enums:"draft,in_review,published,archived"`
+	PublishAt       *string  `json:"publish_at,omitempty"`
+	AuthorID        *int64   `json:"author_id,omitempty"`
+	Author          *Author  `json:"author,omitempty"`
+	Description     string   `json:"description_markdown"`
+	DescriptionHTML string   `json:"description_html"`
 	CreatedAt       string   `json:"created_at"`
 	UpdatedAt       string   `json:"updated_at"`
-}
-
-// SEOData represents SEO metadata for a blog post
-// @swagger:model
-type SEOData struct {
-	// The JSON-LD context
-	Context string `json:"@context"`
-
-	// The type of schema (e.g., BlogPosting)
-	Type string `json:"@type"`
-
-	// The headline of the blog post
-	Headline string `json:"headline"`
-
-	// The keywords associated with the blog post
-	Keywords string `json:"keywords"`
-
-	// The image URL for the blog post
-	Image string `json:"image"`
-
-	// The canonical URL of the blog post
-	URL string `json:"url"`
-}
-
-// URL represents an entry in the sitemap
-// @swagger:model
-type URL struct {
-	// The URL of the blog post
-	Loc string `xml:"loc" json:"loc"`
-
-	// The change frequency of the URL
-	Change string `xml:"changefreq" json:"changefreq"`
-
-	// The priority of the URL in the sitemap
-	Priority string `xml:"priority" json:"priority"`
-}
-
-// Sitemap represents the structure of the sitemap.xml
-// @swagger:model
-type Sitemap struct {
-	XMLName xml.Name `xml:"urlset" json:"-"`
-
-	// List of URLs in the sitemap
-	Urls []URL `xml:"url" json:"urls"`
+	DeletedAt       *string  `json:"deleted_at,omitempty"`
 }
 
 // PaginatedResponse represents a paginated list of blog posts
@@ -111,6 +80,63 @@ type PaginatedResponse struct {
 
 var db *sql.DB
 
+// blogPostColumns lists the blog_posts columns in the order scanBlogPost expects
+const blogPostColumns = `id, title, meta_description, focus_keyword, url_keyword,
+	image, ` + postTagsJSON + `, topic, service, industry, priority, status, description, description_html,
+	publish_at, author_id, ` + postAuthorJSON + `, created_at, updated_at, deleted_at`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// queryer is satisfied by both *sql.DB and *sql.Tx
+type queryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// scanBlogPost scans a row selected with blogPostColumns into a BlogPost.
+// Any extra columns selected after blogPostColumns are scanned into extra.
+func scanBlogPost(row rowScanner, extra ...interface{}) (BlogPost, error) {
+	var tagsJSON string
+	var authorJSON *string
+	var post BlogPost
+	err := row.Scan(append([]interface{}{
+		&post.ID, &post.Title, &post.MetaDescription, &post.FocusKeyword,
+		&post.UrlKeyword, &post.Image, &tagsJSON, &post.Topic,
+		&post.Service, &post.Industry, &post.Priority, &post.Status, &post.Description, &post.DescriptionHTML,
+		&post.PublishAt, &post.AuthorID, &authorJSON, &post.CreatedAt, &post.UpdatedAt, &post.DeletedAt,
+	}, extra...)...)
+	if err != nil {
+		return post, err
+	}
+
+	// Unmarshal the tags JSON if it's not empty
+	if tagsJSON != "" {
+		if err := json.Unmarshal([]byte(tagsJSON), &post.Tags); err != nil {
+			fmt.Println("Error unmarshaling tags:", err)
+			post.Tags = []string{}
+		}
+	}
+	post.ImageURL = absoluteURL(post.Image)
+	post.Author = decodePostAuthor(authorJSON)
+
+	return post, nil
+}
+
+// getBlogPost fetches a single blog post by its URL keyword, ignoring
+// posts that are in the trash
+func getBlogPost(q queryer, urlKeyword string) (BlogPost, error) {
+	return scanBlogPost(q.QueryRow(
+		"SELECT "+blogPostColumns+" FROM blog_posts WHERE url_keyword = ? AND deleted_at IS NULL", urlKeyword))
+}
+
+// getBlogPostByID fetches a single blog post by its ID
+func getBlogPostByID(q queryer, id int64) (BlogPost, error) {
+	return scanBlogPost(q.QueryRow(
+		"SELECT "+blogPostColumns+" FROM blog_posts WHERE id = ?", id))
+}
+
 // Add transaction wrapper
 func withTransaction(fn func(*sql.Tx) error) error {
 	tx, err := db.Begin()
@@ -140,12 +166,19 @@ func writeErrorResponse(w http.ResponseWriter, status int, message string) error
 	return writeJSONResponse(w, status, map[string]string{"error": message})
 }
 
-const (
-	maxFileSize       = 10 << 20 // 10MB
-	allowedImageTypes = "image/jpeg,image/png,image/gif"
-)
+const maxFileSize = 10 << 20 // 10MB
+
+// imageExtensions maps the accepted image types to the extension they are
+// saved with
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
 
-func validateAndSaveFile(file multipart.File, header *multipart.FileHeader) (string, error) {
+// validateAndSaveFile stores an uploaded image under a new name, charging
+// it to the uploader's upload limit and, once saved, their quota
+func validateAndSaveFile(r *http.Request, file multipart.File, header *multipart.FileHeader) (string, error) {
 	// Check file size
 	if header.Size > maxFileSize {
 		return "", fmt.Errorf("file size exceeds maximum allowed size")
@@ -153,27 +186,59 @@ func validateAndSaveFile(file multipart.File, header *multipart.FileHeader) (str
 
 	// Check file type
 	contentType := header.Header.Get("Content-Type")
-	if !strings.Contains(allowedImageTypes, contentType) {
+	ext, ok := imageExtensions[contentType]
+	if !ok {
 		return "", fmt.Errorf("unsupported file type: %s", contentType)
 	}
 
-	// Create safe filename
-	filename := filepath.Clean(header.Filename)
-	filepath := filepath.Join("uploads", filename)
+	if err := checkUpload(r, header.Size); err != nil {
+		return "", err
+	}
+
+	// Name the file randomly rather than after the upload, so posts never
+	// share or overwrite each other's images
+	name := make([]byte, 16)
+	if _, err := rand.Read(name); err != nil {
+		return "", err
+	}
+	path := filepath.Join("uploads", hex.EncodeToString(name)+ext)
 
 	// Save file with proper permissions
-	dst, err := os.OpenFile(filepath, os.O_WRONLY|os.O_CREATE, 0644)
+	dst, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL|os.O_TRUNC, 0644)
 	if err != nil {
 		return "", err
 	}
 	defer dst.Close()
 
-	if _, err := io.Copy(dst, file); err != nil {
-		os.Remove(filepath) // Cleanup on failure
+	written, err := io.Copy(dst, file)
+	if err != nil {
+		os.Remove(path) // Cleanup on failure
 		return "", err
 	}
 
-	return filepath, nil
+	// Only count the file against the quota once it is safely on disk
+	if err := chargeUpload(r, written); err != nil {
+		os.Remove(path)
+		return "", err
+	}
+
+	return path, nil
+}
+
+// discardUpload removes an image saved earlier in the request once saving
+// the post or author that would have used it failed, refunding its quota
+func discardUpload(r *http.Request, image string) {
+	info, err := os.Stat(image)
+	if err != nil {
+		return
+	}
+	if err := removeUnusedImage(image); err != nil {
+		log.Printf("Failed to remove image %s: %v", image, err)
+		return
+	}
+	if _, err := os.Stat(image); os.IsNotExist(err) {
+		refundUpload(r, info.Size())
+	}
 }
 
 var PriorityWeight = map[string]int{
@@ -189,25 +254,6 @@ func wrapHandler(handler http.Handler) http.HandlerFunc {
 	}
 }
 
-// CORS middleware to handle cross-origin requests
-func corsMiddleware(next http.HandlerFunc) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		w.Header().Set("Access-Control-Allow-Credentials", "true")
-
-		// Handle preflight requests
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
-
-		next(w, r)
-	}
-}
-
 func fileServerHandler(dir string) http.HandlerFunc {
 	// Create a file server handler for the uploads directory
 	fs := http.FileServer(http.Dir(dir))
@@ -241,10 +287,16 @@ func main() {
 	if err := godotenv.Load(); err != nil {
 		log.Println("⚠️ Warning: No .env file found. Using default values if available.")
 	}
+	site = loadSiteConfig()
+	loadJWTConfig()
+	cors := loadCORSPolicy()
 
 	// Initialize SQLite database
 	var err error
-	db, err = sql.Open("sqlite3", "../blog.db")
+	// Wait on locks instead of failing with SQLITE_BUSY, and take the write
+	// lock up front so transactions from the scheduler and handlers don't
+	// deadlock upgrading from read to write
+	db, err = sql.Open("sqlite3", "../blog.db?_busy_timeout=5000&_txlock=immediate")
 	if err != nil {
 		log.Fatal("❌ Failed to open database:", err)
 	}
@@ -253,16 +305,66 @@ func main() {
 	// Create tables if they don't exist
 	createTables()
 
-	// Apply CORS middleware to all routes
-	http.HandleFunc("/blog", corsMiddleware(createBlogHandler))
-	http.HandleFunc("/blog/", corsMiddleware(blogHandler))
-	http.HandleFunc("/blogs", corsMiddleware(listBlogsHandler))
-	http.HandleFunc("/sitemap.xml", corsMiddleware(sitemapHandler))
+	// Manage API keys and users from the command line instead of serving
+	if len(os.Args) > 1 && (os.Args[1] == "keys" || os.Args[1] == "users") {
+		run := runKeysCommand
+		if os.Args[1] == "users" {
+			run = runUsersCommand
+		}
+		if err := run(os.Args[2:]); err != nil {
+			log.Fatal("❌ ", err)
+		}
+		return
+	}
+
+	// Publish scheduled posts, catching up on any missed while we were down
+	startPublisher(durationFromEnv("PUBLISH_INTERVAL", defaultPublishInterval))
+
+	limiter := loadRateLimiter()
+
+	// Apply CORS, authentication and rate limiting middleware to all routes;
+	// handlers that change anything also require a role
+	handle := func(pattern string, handler http.HandlerFunc) {
+		http.HandleFunc(pattern, cors.Handler(limiter.Guard(authMiddleware(limiter.Handler(handler)))))
+	}
+
+	handle("/blog", requireRole(RoleAuthor, createBlogHandler))
+	handle("/blog/", blogRouter)
+	handle("/blogs", listBlogsHandler)
+	handle("/tags", listTagsHandler)
+	handle("/tags/", tagsRouter)
+	handle("/authors", authorsRouter)
+	handle("/authors/", authorsRouter)
+	handle("/trash", requireRole(RoleViewer, listTrashHandler))
+	handle("/trash/", trashRouter)
+	handle("/keys", requireRole(RoleAdmin, keysRouter))
+	handle("/keys/", requireRole(RoleAdmin, keysRouter))
+	handle("/users", requireRole(RoleAdmin, usersRouter))
+	handle("/users/", requireRole(RoleAdmin, usersRouter))
+	handle("/auth/logout", logoutHandler)
+	// Logging in and refreshing work even if a stale access token is still sent
+	http.HandleFunc("/auth/login", cors.Handler(limiter.Guard(limiter.Handler(loginHandler))))
+	http.HandleFunc("/auth/refresh", cors.Handler(limiter.Guard(limiter.Handler(refreshHandler))))
+	handle("/search", searchHandler)
+	handle("/sitemap.xml", sitemapHandler)
+	handle("/sitemaps/", sitemapPageHandler)
+	handle("/feed.rss", feedHandler)
+	handle("/feed.atom", feedHandler)
+	handle("/feed.json", feedHandler)
+	handle("/feeds/", feedHandler)
+
+	if site.HTMLPages {
+		if pageTemplates, err = loadTemplates(site.TemplatesDir); err != nil {
+			log.Fatal("❌ Failed to load templates:", err)
+		}
+		handle("/posts", postIndexHandler)
+		handle("/posts/", postPageHandler)
+	}
 
 	// For the swagger handler, we need to wrap it since it's an http.Handler
-	http.HandleFunc("/swagger/", corsMiddleware(wrapHandler(httpSwagger.WrapHandler)))
+	handle("/swagger/", wrapHandler(httpSwagger.WrapHandler))
 
-	http.HandleFunc("/uploads/", corsMiddleware(fileServerHandler("./uploads")))
+	handle("/uploads/", fileServerHandler("./uploads"))
 
 	log.Println("🚀 Server running on port 8080...")
 	log.Fatal(http.ListenAndServe(":8080", nil))
@@ -277,39 +379,238 @@ func createTables() {
 		focus_keyword TEXT,
 		url_keyword TEXT NOT NULL,
 		image TEXT,
-		tags TEXT,
 		topic TEXT,
 		service TEXT,
 		industry TEXT,
 		priority TEXT,
+		status TEXT NOT NULL DEFAULT 'published',
+		publish_at DATETIME,
+		author_id INTEGER,
 		description TEXT NOT NULL,
+		description_html TEXT NOT NULL DEFAULT '',
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		deleted_at DATETIME
 	);
 	CREATE INDEX IF NOT EXISTS idx_url_keyword ON blog_posts(url_keyword);
 	CREATE INDEX IF NOT EXISTS idx_priority ON blog_posts(priority);
+
+	CREATE TABLE IF NOT EXISTS post_revisions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		post_id INTEGER NOT NULL,
+		revision INTEGER NOT NULL,
+		title TEXT NOT NULL,
+		meta_description TEXT,
+		focus_keyword TEXT,
+		url_keyword TEXT NOT NULL,
+		image TEXT,
+		tags TEXT,
+		topic TEXT,
+		service TEXT,
+		industry TEXT,
+		priority TEXT,
+		status TEXT,
+		publish_at DATETIME,
+		author_id INTEGER,
+		description TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(post_id, revision)
+	);
+
+	CREATE TABLE IF NOT EXISTS tags (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS post_tags (
+		post_id INTEGER NOT NULL,
+		tag_id INTEGER NOT NULL,
+		position INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (post_id, tag_id)
+	);
+	CREATE INDEX IF NOT EXISTS idx_post_tags_tag ON post_tags(tag_id);
+
+	CREATE TABLE IF NOT EXISTS slug_redirects (
+		old_slug TEXT PRIMARY KEY,
+		new_slug TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+	CREATE INDEX IF NOT EXISTS idx_redirect_new_slug ON slug_redirects(new_slug);
+
+	CREATE TABLE IF NOT EXISTS api_keys (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		role TEXT NOT NULL,
+		prefix TEXT NOT NULL,
+		key_hash TEXT NOT NULL UNIQUE,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		last_used_at DATETIME,
+		revoked_at DATETIME
+	);
+
+	CREATE TABLE IF NOT EXISTS authors (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		slug TEXT NOT NULL UNIQUE,
+		bio TEXT NOT NULL DEFAULT '',
+		avatar TEXT NOT NULL DEFAULT '',
+		links TEXT NOT NULL DEFAULT '[]',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		email TEXT NOT NULL UNIQUE COLLATE NOCASE,
+		name TEXT NOT NULL,
+		role TEXT NOT NULL,
+		password_hash TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS sessions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		last_used_at DATETIME,
+		expires_at DATETIME NOT NULL,
+		revoked_at DATETIME
+	);
+	CREATE INDEX IF NOT EXISTS idx_sessions_user ON sessions(user_id);
+
+	CREATE TABLE IF NOT EXISTS refresh_tokens (
+		token_hash TEXT PRIMARY KEY,
+		session_id INTEGER NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		used_at DATETIME
+	);
+	CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session ON refresh_tokens(session_id);
+
+	CREATE TABLE IF NOT EXISTS upload_usage (
+		client TEXT NOT NULL,
+		day TEXT NOT NULL,
+		bytes INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (client, day)
+	);
 	`
 	_, err := db.Exec(query)
 	if err != nil {
 		log.Fatal("❌ Failed to create tables:", err)
 	}
+
+	// Bring databases created by older versions up to date
+	for _, m := range columnMigrations {
+		if err := addColumnIfMissing(m.table, m.column, m.definition); err != nil {
+			log.Fatal("❌ Failed to migrate tables:", err)
+		}
+	}
+
+	// Move tags out of the legacy JSON column into tags/post_tags
+	if err := migrateLegacyTags(); err != nil {
+		log.Fatal("❌ Failed to migrate tags:", err)
+	}
+
+	// Render descriptions saved before they were stored as HTML too
+	if err := backfillDescriptionHTML(); err != nil {
+		log.Fatal("❌ Failed to render descriptions:", err)
+	}
+
+	indexes := `
+	CREATE INDEX IF NOT EXISTS idx_deleted_at ON blog_posts(deleted_at);
+	CREATE INDEX IF NOT EXISTS idx_status ON blog_posts(status);
+	CREATE INDEX IF NOT EXISTS idx_publish_at ON blog_posts(publish_at);
+	CREATE INDEX IF NOT EXISTS idx_author_id ON blog_posts(author_id);
+	`
+	if _, err := db.Exec(indexes); err != nil {
+		log.Fatal("❌ Failed to create indexes:", err)
+	}
+
+	// Give posts written before revisions existed their first revision
+	if _, err := db.Exec(revisionSnapshotQuery +
+		" WHERE id NOT IN (SELECT post_id FROM post_revisions)"); err != nil {
+		log.Fatal("❌ Failed to backfill revisions:", err)
+	}
+
+	createSearchIndex()
+}
+
+// columnMigrations lists columns added to existing tables after their
+// first release. Fresh databases get them from CREATE TABLE directly.
+var columnMigrations = []struct {
+	table, column, definition string
+}{
+	{"blog_posts", "deleted_at", "DATETIME"},
+	{"blog_posts", "status", "TEXT NOT NULL DEFAULT 'published'"},
+	{"blog_posts", "publish_at", "DATETIME"},
+	{"blog_posts", "description_html", "TEXT NOT NULL DEFAULT ''"},
+	{"blog_posts", "author_id", "INTEGER"},
+	{"post_revisions", "author_id", "INTEGER"},
+}
+
+// addColumnIfMissing adds a column to a table unless it already exists
+func addColumnIfMissing(table, column, definition string) error {
+	exists, err := hasColumn(table, column)
+	if err != nil || exists {
+		return err
+	}
+
+	_, err = db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
+
+// hasColumn reports whether a table has the given column
+func hasColumn(table, column string) (bool, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid, notNull, pk int
+			name, colType    string
+			defaultValue     sql.NullString
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
 }
 
 // listBlogsHandler handles listing blogs with pagination
 // @Summary List blog posts
-// @Description Get a paginated list of blog posts
+// @Description Get a paginated list of blog posts, optionally filtered. Totals reflect the filtered set.
 // @Tags blogs
 // @Accept json
 // @Produce json
 // @Param page query int false "Page number"
 // @Param pageSize query int false "Number of items per page"
+// @Param status query string false "Only posts with this status (authenticated callers only)" Enums(draft, in_review, published, archived)
+// @Param tags query string false "Comma-separated tags to filter by"
+// @Param tags_match query string false "Whether posts need any or all of the tags (default any)" Enums(any, all)
+// @Param topic query string false "Topic"
+// @Param service query string false "Service"
+// @Param industry query string false "Industry"
+// @Param author query string false "Slug of the author"
+// @Param priority query string false "Comma-separated priorities" Enums(maximum, high, normal)
+// @Param created_from query string false "Created on or after this date (YYYY-MM-DD or RFC 3339)"
+// @Param created_to query string false "Created on or before this date (YYYY-MM-DD or RFC 3339)"
+// @Param sort query string false "Comma-separated sort keys (created_at, updated_at, title, priority), prefix with - to reverse. priority sorts most important first. Defaults to -created_at"
+// @Param cursor query string false "Switches to cursor pagination. Pass an empty value for the first page, then next_cursor or prev_cursor; the response is a CursorResponse"
+// @Param count query bool false "In cursor mode, also return totalPosts"
 // @Success 200 {object} PaginatedResponse
+// @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /blogs [get]
 func listBlogsHandler(w http.ResponseWriter, r *http.Request) {
 	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
 	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
-	sortByPriority := r.URL.Query().Get("sort") == "priority"
 
 	if page < 1 {
 		page = 1
@@ -318,23 +619,39 @@ func listBlogsHandler(w http.ResponseWriter, r *http.Request) {
 		pageSize = 10
 	}
 
+	filter, err := parsePostFilter(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	where, args := filter.where()
+
+	// Keyset pagination replaces page/offset paging when a cursor is given
+	if r.URL.Query().Has("cursor") {
+		listBlogsByCursor(w, r, where, args, pageSize)
+		return
+	}
+
+	sortKeys, err := parseSort(r.URL.Query().Get("sort"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	// Count total posts
 	var totalPosts int
-	err := db.QueryRow("SELECT COUNT(*) FROM blog_posts").Scan(&totalPosts)
+	err = db.QueryRow("SELECT COUNT(*) FROM blog_posts WHERE "+where, args...).Scan(&totalPosts)
 	if err != nil {
 		http.Error(w, "Could not count blog posts", http.StatusInternalServerError)
 		return
 	}
 
 	// Prepare query
-	query := "SELECT * FROM blog_posts"
-	if sortByPriority {
-		query += " ORDER BY CASE priority WHEN 'maximum' THEN 1 WHEN 'high' THEN 2 WHEN 'normal' THEN 3 ELSE 4 END"
-	}
-	query += " LIMIT ? OFFSET ?"
+	query := "SELECT " + blogPostColumns + " FROM blog_posts WHERE " + where +
+		" ORDER BY " + orderByClause(sortKeys) + " LIMIT ? OFFSET ?"
 
 	offset := (page - 1) * pageSize
-	rows, err := db.Query(query, pageSize, offset)
+	rows, err := db.Query(query, append(args, pageSize, offset)...)
 	if err != nil {
 		http.Error(w, "Could not fetch blog posts", http.StatusInternalServerError)
 		return
@@ -343,23 +660,7 @@ func listBlogsHandler(w http.ResponseWriter, r *http.Request) {
 
 	var posts []BlogPost
 	for rows.Next() {
-		var tagsJSON string
-		var post BlogPost
-		err := rows.Scan(
-			&post.ID, &post.Title, &post.MetaDescription, &post.FocusKeyword,
-			&post.UrlKeyword, &post.Image, &tagsJSON, &post.Topic,
-			&post.Service, &post.Industry, &post.Priority, &post.Description,
-			&post.CreatedAt, &post.UpdatedAt,
-		)
-		// Unmarshal the tags JSON if it's not empty
-		if tagsJSON != "" {
-			if err := json.Unmarshal([]byte(tagsJSON), &post.Tags); err != nil {
-				fmt.Println("Error unmarshaling tags:", err)
-				http.Error(w, "Error unmarshaling tags", http.StatusInternalServerError)
-				post.Tags = []string{}
-			}
-		}
-
+		post, err := scanBlogPost(rows)
 		if err != nil {
 			continue
 		}
@@ -380,6 +681,44 @@ func listBlogsHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// blogPathParts splits /blog/{urlKeyword}/{action} into its URL keyword and
+// the optional sub-resource action
+func blogPathParts(r *http.Request) (urlKeyword, action string) {
+	urlKeyword, action, _ = strings.Cut(strings.TrimPrefix(r.URL.Path, "/blog/"), "/")
+	return urlKeyword, action
+}
+
+// blogRouter dispatches /blog/{urlKeyword} requests by sub-resource and HTTP method
+func blogRouter(w http.ResponseWriter, r *http.Request) {
+	_, action := blogPathParts(r)
+
+	switch {
+	case action == "":
+		switch r.Method {
+		case http.MethodGet:
+			blogHandler(w, r)
+		case http.MethodPut, http.MethodPatch:
+			requireRole(RoleAuthor, updateBlogHandler)(w, r)
+		case http.MethodDelete:
+			requireRole(RoleEditor, deleteBlogHandler)(w, r)
+		default:
+			w.Header().Set("Allow", "GET, PUT, PATCH, DELETE")
+			writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	case action == "status":
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		requireRole(RoleAuthor, updateStatusHandler)(w, r)
+	case action == "revisions" || strings.HasPrefix(action, "revisions/"):
+		revisionsRouter(w, r)
+	default:
+		writeErrorResponse(w, http.StatusNotFound, "Not found")
+	}
+}
+
 // blogHandler retrieves a blog post by its URL keyword
 // @Summary Get a blog post
 // @Description Retrieve a blog post by its URL keyword
@@ -388,27 +727,20 @@ func listBlogsHandler(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param urlKeyword path string true "URL Keyword of the blog post"
 // @Success 200 {object} BlogPost
+// @Success 301 "Moved permanently to the post's current URL keyword"
 // @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /blog/{urlKeyword} [get]
 func blogHandler(w http.ResponseWriter, r *http.Request) {
-	urlKeyword := r.URL.Path[len("/blog/"):]
-
-	var tagsJSON string
-
-	var blog BlogPost
-	err := db.QueryRow(`
-		SELECT id, title, meta_description, focus_keyword, url_keyword,
-			image, tags, topic, service, industry, priority, description,
-		  created_at, updated_at
-		FROM blog_posts WHERE url_keyword = ?`, urlKeyword).Scan(
-		&blog.ID, &blog.Title, &blog.MetaDescription, &blog.FocusKeyword,
-		&blog.UrlKeyword, &blog.Image, &tagsJSON, &blog.Topic,
-		&blog.Service, &blog.Industry, &blog.Priority, &blog.Description,
-		&blog.CreatedAt, &blog.UpdatedAt,
-	)
+	urlKeyword, _ := blogPathParts(r)
 
+	blog, err := getVisibleBlogPost(r, urlKeyword)
 	if err == sql.ErrNoRows {
+		// The slug may have been renamed, in which case send the client on
+		if target, ok := resolveRedirect(urlKeyword); ok {
+			redirectToSlug(w, r, "/blog/", target)
+			return
+		}
 		http.Error(w, "Blog post not found", http.StatusNotFound)
 		return
 	} else if err != nil {
@@ -417,79 +749,27 @@ func blogHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Unmarshal the tags JSON if it's not empty
-	if tagsJSON != "" {
-		if err := json.Unmarshal([]byte(tagsJSON), &blog.Tags); err != nil {
-			fmt.Println("Error unmarshaling tags:", err)
-			http.Error(w, "Error unmarshaling tags", http.StatusInternalServerError)
-			blog.Tags = []string{}
-		}
-	}
-
-	blogURL := "/blog/" + blog.UrlKeyword
-
-	seoData := SEOData{
-		Context:  "https://schema.org",
-		Type:     "BlogPosting",
-		Headline: blog.Title,
-		Keywords: blog.FocusKeyword,
-		Image:    blog.Image,
-		URL:      blogURL,
-	}
+	seoData := buildSEOData(blog)
 
 	response := map[string]interface{}{
-		"blog":      blog,
-		"seoData":   seoData,
-		"canonical": blogURL,
+		"blog":        blog,
+		"seoData":     seoData,
+		"openGraph":   buildOpenGraph(blog),
+		"twitterCard": buildTwitterCard(blog),
+		"canonical":   postURL(blog.UrlKeyword),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
-// sitemapHandler generates a sitemap
-// @Summary Generate sitemap.xml
-// @Description Generate an XML sitemap of blog posts
-// @Tags sitemap
-// @Produce xml
-// @Success 200 {object} Sitemap
-// @Failure 500 {object} map[string]string
-// @Router /sitemap.xml [get]
-func sitemapHandler(w http.ResponseWriter, r *http.Request) {
-	rows, err := db.Query("SELECT url_keyword, priority FROM blog_posts")
-
-	if err != nil {
-		http.Error(w, "Could not generate sitemap", http.StatusInternalServerError)
-		return
-	}
-	defer rows.Close()
-
-	var urls []URL
-
-	for rows.Next() {
-		var urlKeyword, priority string
-		if err := rows.Scan(&urlKeyword, &priority); err != nil {
-			continue
-		}
-
-		urls = append(urls, URL{
-			Loc:      "/blog/" + urlKeyword,
-			Change:   "weekly",
-			Priority: priority,
-		})
-	}
-
-	sitemap := Sitemap{Urls: urls}
-	w.Header().Set("Content-Type", "application/xml")
-	xml.NewEncoder(w).Encode(sitemap)
-}
-
 // createBlogHandler creates a new blog post with image upload
 // @Summary Create a new blog post
 // @Description Create a new blog post with metadata and an optional image upload
 // @Tags blogs
 // @Accept multipart/form-data
 // @Produce json
+// @Security BearerAuth
 // @Param title formData string true "Title"
 // @Param meta_description formData string false "Meta Description"
 // @Param focus_keyword formData string false "Focus Keyword"
@@ -499,10 +779,16 @@ func sitemapHandler(w http.ResponseWriter, r *http.Request) {
 // @Param service formData string false "Service"
 // @Param industry formData string false "Industry"
 // @Param priority formData string false "Priority"
-// @Param description formData string true "Description"
+// @Param status formData string false "Initial status (defaults to published, or draft when publish_at is set)" Enums(draft, in_review, published, archived)
+// @Param publish_at formData string false "RFC 3339 time at which a draft is published automatically"
+// @Param author formData string false "Slug of the post's author"
+// @Param description formData string true "Description in Markdown"
 // @Param image formData file false "Image file (optional)"
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 429 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /blog [post]
 func createBlogHandler(w http.ResponseWriter, r *http.Request) {
@@ -511,67 +797,269 @@ func createBlogHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	blog, err := validateBlogPost(r)
+	blog, err := validateBlogPost(r, 0)
 	if err != nil {
 		writeErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	// Authors' posts start as drafts; only editors publish or schedule
+	isEditor := hasRole(r, RoleEditor)
+	blog.Status = strings.TrimSpace(r.FormValue("status"))
+	if blog.Status == "" {
+		blog.Status = StatusPublished
+		if blog.PublishAt != nil || !isEditor {
+			blog.Status = StatusDraft
+		}
+	} else if _, ok := statusTransitions[blog.Status]; !ok {
+		writeErrorResponse(w, http.StatusBadRequest, "invalid status value: must be draft, in_review, published, or archived")
+		return
+	}
+	if !isEditor && (isEditorialStatus(blog.Status) || blog.PublishAt != nil) {
+		writeErrorResponse(w, http.StatusForbidden, "publishing or scheduling posts requires the editor role")
+		return
+	}
+	if blog.PublishAt != nil && blog.Status != StatusDraft {
+		writeErrorResponse(w, http.StatusBadRequest, "publish_at can only be set on draft posts")
+		return
+	}
+
 	// Handle file upload
 	if file, header, err := r.FormFile("image"); err == nil {
-		if filepath, err := validateAndSaveFile(file, header); err != nil {
-			writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("Invalid file: %v", err))
+		if filepath, err := validateAndSaveFile(r, file, header); err != nil {
+			writeUploadError(w, err, "Invalid file")
 			return
 		} else {
 			blog.Image = filepath
 		}
 	}
 
-	tagsJSON, err := json.Marshal(blog.Tags)
-	if err != nil {
-		writeErrorResponse(w, http.StatusInternalServerError, "Failed to process tags")
-		return
-	}
-
 	// Use transaction for database operation
 	err = withTransaction(func(tx *sql.Tx) error {
+		if err := claimSlug(tx, blog.UrlKeyword); err != nil {
+			return err
+		}
+
 		result, err := tx.Exec(`
         INSERT INTO blog_posts (
             title, meta_description, focus_keyword, url_keyword,
-            image, tags, topic, service, industry, priority, description
-        ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+            image, topic, service, industry, priority, status, publish_at,
+            author_id, description, description_html
+        ) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 			blog.Title, blog.MetaDescription, blog.FocusKeyword, blog.UrlKeyword,
-			blog.Image, string(tagsJSON), blog.Topic, blog.Service, blog.Industry,
-			blog.Priority, blog.Description,
+			blog.Image, blog.Topic, blog.Service, blog.Industry,
+			blog.Priority, blog.Status, blog.PublishAt, blog.AuthorID, blog.Description, blog.DescriptionHTML,
 		)
 		if err != nil {
 			return err
 		}
 		blog.ID, err = result.LastInsertId()
-		return err
+		if err != nil {
+			return err
+		}
+		if err := setPostTags(tx, blog.ID, blog.Tags); err != nil {
+			return err
+		}
+		if err := indexPost(tx, blog.ID); err != nil {
+			return err
+		}
+		return recordRevision(tx, blog.ID)
 	})
 
 	if err != nil {
-		if blog.Image != "" {
-			os.Remove(blog.Image) // Cleanup uploaded file on DB failure
-		}
+		discardUpload(r, blog.Image)
 		fmt.Println(err)
 		writeErrorResponse(w, http.StatusInternalServerError, "Failed to create blog post")
 		return
 	}
 
 	if err := writeJSONResponse(w, http.StatusCreated, map[string]interface{}{
-		"message": "Blog post created successfully",
-		"url":     "/blog/" + blog.UrlKeyword,
-		"id":      blog.ID,
-		"image":   blog.Image,
-		"tags":    blog.Tags,
+		"message":    "Blog post created successfully",
+		"url":        "/blog/" + blog.UrlKeyword,
+		"id":         blog.ID,
+		"image":      blog.Image,
+		"image_url":  absoluteURL(blog.Image),
+		"tags":       blog.Tags,
+		"status":     blog.Status,
+		"publish_at": blog.PublishAt,
 	}); err != nil {
 		log.Printf("Failed to write response: %v", err)
 	}
 }
 
-func validateBlogPost(r *http.Request) (BlogPost, error) {
+// updateBlogHandler replaces or partially updates an existing blog post
+// @Summary Update a blog post
+// @Description Replace (PUT) or partially update (PATCH) a blog post. PATCH keeps any field that is not sent.
+// @Description The current image is kept unless a new one is uploaded.
+// @Description Published and archived posts are live, so only editors may change them.
+// @Tags blogs
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param urlKeyword path string true "URL Keyword of the blog post"
+// @Param title formData string false "Title (required for PUT)"
+// @Param meta_description formData string false "Meta Description"
+// @Param focus_keyword formData string false "Focus Keyword"
+// @Param url_keyword formData string false "URL Keyword (required for PUT)"
+// @Param tags formData array false "Tags (comma-separated values or multiple fields)"
+// @Param topic formData string false "Topic"
+// @Param service formData string false "Service"
+// @Param industry formData string false "Industry"
+// @Param priority formData string false "Priority"
+// @Param description formData string false "Description in Markdown (required for PUT)"
+// @Param publish_at formData string false "RFC 3339 time at which the draft is published automatically; empty clears it"
+// @Param author formData string false "Slug of the post's author; empty clears it"
+// @Param image formData file false "Replacement image file (optional)"
+// @Success 200 {object} BlogPost
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /blog/{urlKeyword} [put]
+// @Router /blog/{urlKeyword} [patch]
+func updateBlogHandler(w http.ResponseWriter, r *http.Request) {
+	urlKeyword, _ := blogPathParts(r)
+
+	existing, err := getBlogPost(db, urlKeyword)
+	if err == sql.ErrNoRows {
+		writeErrorResponse(w, http.StatusNotFound, "Blog post not found")
+		return
+	} else if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+	if isEditorialStatus(existing.Status) && !hasRole(r, RoleEditor) {
+		writeErrorResponse(w, http.StatusForbidden, "editing published or archived posts requires the editor role")
+		return
+	}
+
+	if err := r.ParseMultipartForm(maxFileSize); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Failed to parse form data")
+		return
+	}
+
+	// PATCH only touches the fields that were sent, so fill in the rest
+	// from the stored post before running the usual validation
+	if r.Method == http.MethodPatch {
+		mergeBlogPostForm(r.Form, existing)
+	}
+
+	blog, err := validateBlogPost(r, existing.ID)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	blog.ID = existing.ID
+	blog.Image = existing.Image
+
+	if blog.PublishAt != nil && existing.Status != StatusDraft {
+		writeErrorResponse(w, http.StatusBadRequest, "publish_at can only be set on draft posts")
+		return
+	}
+	if !hasRole(r, RoleEditor) && !samePublishAt(blog.PublishAt, existing.PublishAt) {
+		writeErrorResponse(w, http.StatusForbidden, "scheduling posts requires the editor role")
+		return
+	}
+
+	// Handle file upload
+	if file, header, err := r.FormFile("image"); err == nil {
+		if filepath, err := validateAndSaveFile(r, file, header); err != nil {
+			writeUploadError(w, err, "Invalid file")
+			return
+		} else {
+			blog.Image = filepath
+		}
+	}
+
+	var updated BlogPost
+	err = withTransaction(func(tx *sql.Tx) error {
+		if blog.UrlKeyword != existing.UrlKeyword {
+			if err := recordSlugChange(tx, existing.UrlKeyword, blog.UrlKeyword); err != nil {
+				return err
+			}
+		}
+
+		_, err := tx.Exec(`
+        UPDATE blog_posts SET
+            title = ?, meta_description = ?, focus_keyword = ?, url_keyword = ?,
+            image = ?, topic = ?, service = ?, industry = ?, priority = ?,
+            publish_at = ?, author_id = ?, description = ?, description_html = ?,
+            updated_at = CURRENT_TIMESTAMP
+        WHERE id = ?`,
+			blog.Title, blog.MetaDescription, blog.FocusKeyword, blog.UrlKeyword,
+			blog.Image, blog.Topic, blog.Service, blog.Industry,
+			blog.Priority, blog.PublishAt, blog.AuthorID, blog.Description, blog.DescriptionHTML, blog.ID,
+		)
+		if err != nil {
+			return err
+		}
+		if err := setPostTags(tx, blog.ID, blog.Tags); err != nil {
+			return err
+		}
+		if err := indexPost(tx, blog.ID); err != nil {
+			return err
+		}
+		if err := recordRevision(tx, blog.ID); err != nil {
+			return err
+		}
+		updated, err = getBlogPostByID(tx, blog.ID)
+		return err
+	})
+
+	if err != nil {
+		if blog.Image != existing.Image {
+			discardUpload(r, blog.Image)
+		}
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to update blog post")
+		return
+	}
+
+	if updated.Image != existing.Image {
+		if err := removeUnusedImage(existing.Image); err != nil {
+			log.Printf("Failed to remove image %s: %v", existing.Image, err)
+		}
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, updated); err != nil {
+		log.Printf("Failed to write response: %v", err)
+	}
+}
+
+// mergeBlogPostForm fills form fields missing from a PATCH request with the
+// stored values so that validateBlogPost sees a complete post
+func mergeBlogPostForm(form url.Values, post BlogPost) {
+	fields := map[string]string{
+		"title":            post.Title,
+		"meta_description": post.MetaDescription,
+		"focus_keyword":    post.FocusKeyword,
+		"url_keyword":      post.UrlKeyword,
+		"topic":            post.Topic,
+		"service":          post.Service,
+		"industry":         post.Industry,
+		"priority":         post.Priority,
+		"description":      post.Description,
+		"tags":             strings.Join(post.Tags, ","),
+	}
+	if post.PublishAt != nil {
+		fields["publish_at"] = *post.PublishAt
+	}
+	if post.Author != nil {
+		fields["author"] = post.Author.Slug
+	}
+	for name, value := range fields {
+		if _, ok := form[name]; !ok {
+			form.Set(name, value)
+		}
+	}
+}
+
+// validateBlogPost validates the submitted form. excludeID is the ID of the
+// post being updated, so its own url_keyword is not reported as a duplicate.
+func validateBlogPost(r *http.Request, excludeID int64) (BlogPost, error) {
 	var blog BlogPost
 
 	// Required field validation
@@ -584,6 +1072,11 @@ func validateBlogPost(r *http.Request) (BlogPost, error) {
 	if blog.Description == "" {
 		return blog, fmt.Errorf("description is required")
 	}
+	rendered, err := renderMarkdown(blog.Description)
+	if err != nil {
+		return blog, fmt.Errorf("description could not be rendered: %v", err)
+	}
+	blog.DescriptionHTML = rendered
 
 	blog.UrlKeyword = strings.TrimSpace(r.FormValue("url_keyword"))
 	if blog.UrlKeyword == "" {
@@ -637,6 +1130,18 @@ func validateBlogPost(r *http.Request) (BlogPost, error) {
 	blog.Service = strings.TrimSpace(r.FormValue("service"))
 	blog.Industry = strings.TrimSpace(r.FormValue("industry"))
 
+	publishAt, err := parsePublishAt(r.FormValue("publish_at"))
+	if err != nil {
+		return blog, err
+	}
+	blog.PublishAt = publishAt
+
+	if blog.AuthorID, err = resolveAuthorID(r.FormValue("author")); err == errAuthorNotFound {
+		return blog, fmt.Errorf("author must be the slug of an existing author")
+	} else if err != nil {
+		return blog, fmt.Errorf("failed to look up author: %v", err)
+	}
+
 	// Optional field validations
 	if len(blog.MetaDescription) > 160 {
 		return blog, fmt.Errorf("meta description cannot exceed 160 characters")
@@ -648,7 +1153,7 @@ func validateBlogPost(r *http.Request) (BlogPost, error) {
 
 	// Check for duplicate URL keyword
 	var exists bool
-	err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM blog_posts WHERE url_keyword = ?)", blog.UrlKeyword).Scan(&exists)
+	err = db.QueryRow("SELECT EXISTS(SELECT 1 FROM blog_posts WHERE url_keyword = ? AND id != ?)", blog.UrlKeyword, excludeID).Scan(&exists)
 	if err != nil {
 		return blog, fmt.Errorf("failed to check URL keyword uniqueness: %v", err)
 	}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+// Descriptions are written in Markdown (GitHub flavoured, so tables, task
+// lists and strikethrough work) and rendered to HTML when a post is saved.
+// Raw HTML is let through the Markdown renderer so older HTML descriptions
+// keep working, and everything is then sanitized against bluemonday's
+// allowlist for user generated content.
+var (
+	markdown = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithRendererOptions(html.WithUnsafe()),
+	)
+
+	sanitizer = newSanitizer()
+)
+
+func newSanitizer() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	// Keep the language of fenced code blocks for syntax highlighters
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+-]+$`)).OnElements("code")
+	return policy
+}
+
+// renderMarkdown converts a Markdown description into sanitized HTML
+func renderMarkdown(source string) (string, error) {
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	return sanitizer.Sanitize(buf.String()), nil
+}
+
+// backfillDescriptionHTML renders the descriptions of posts saved before
+// description_html existed
+func backfillDescriptionHTML() error {
+	rows, err := db.Query("SELECT id, description FROM blog_posts WHERE description_html = '' AND description != ''")
+	if err != nil {
+		return err
+	}
+	descriptions := map[int64]string{}
+	for rows.Next() {
+		var id int64
+		var description string
+		if err := rows.Scan(&id, &description); err != nil {
+			rows.Close()
+			return err
+		}
+		descriptions[id] = description
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return withTransaction(func(tx *sql.Tx) error {
+		for id, description := range descriptions {
+			rendered, err := renderMarkdown(description)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Exec("UPDATE blog_posts SET description_html = ? WHERE id = ?", rendered, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"embed"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// The built-in theme. Any of its templates can be replaced by a file of the
+// same name in TEMPLATES_DIR; the rest keep their built-in version.
+//
+//go:embed templates/*.html
+var defaultTemplates embed.FS
+
+// htmlPageSize is the number of posts on each page of the HTML index
+const htmlPageSize = 10
+
+// pageTemplates holds the parsed theme, loaded at startup when HTML pages are enabled
+var pageTemplates *template.Template
+
+// PageHead is what every page puts in its <head>
+type PageHead struct {
+	Title       string
+	Description string
+	Canonical   string
+	PrevURL     string
+	NextURL     string
+	JSONLD      interface{}
+	OpenGraph   *OpenGraph
+	TwitterCard *TwitterCard
+}
+
+// PostPage is the data the post.html template is rendered with
+type PostPage struct {
+	Head PageHead
+	Site SiteConfig
+	Post BlogPost
+
+	// The post's description, already sanitized when it was saved
+	Content template.HTML
+}
+
+// IndexPage is the data the index.html template is rendered with
+type IndexPage struct {
+	Head       PageHead
+	Site       SiteConfig
+	Heading    string
+	Posts      []BlogPost
+	Page       int
+	TotalPages int
+
+	// The author whose posts are listed, if any
+	Author *Author
+}
+
+// metaTag is a single <meta> element
+type metaTag struct {
+	Name    string
+	Content string
+}
+
+// postPath is the public path of a post: its HTML page when HTML pages are
+// enabled, its API resource otherwise
+func postPath(urlKeyword string) string {
+	if site.HTMLPages {
+		return "/posts/" + urlKeyword
+	}
+	return "/blog/" + urlKeyword
+}
+
+// postURL is the absolute public URL of a post, used as its canonical URL
+func postURL(urlKeyword string) string {
+	return absoluteURL(postPath(urlKeyword))
+}
+
+// listingPath is the path of the post listing filtered by a single value,
+// e.g. every post of a topic
+func listingPath(param, value string) string {
+	path := "/blogs"
+	if site.HTMLPages {
+		path = "/posts"
+	}
+	return path + "?" + url.Values{param: {value}}.Encode()
+}
+
+// metaTags flattens a struct of meta tags keyed by their JSON names, such as
+// OpenGraph, into <meta> elements. Empty fields are skipped and slices
+// repeat the tag once per element.
+func metaTags(tags interface{}) []metaTag {
+	v := reflect.Indirect(reflect.ValueOf(tags))
+	if !v.IsValid() || v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var meta []metaTag
+	for i := 0; i < v.NumField(); i++ {
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+		field := v.Field(i)
+		if name == "" || field.IsZero() {
+			continue
+		}
+		if field.Kind() == reflect.Slice {
+			for j := 0; j < field.Len(); j++ {
+				meta = append(meta, metaTag{name, fmt.Sprint(field.Index(j).Interface())})
+			}
+			continue
+		}
+		meta = append(meta, metaTag{name, fmt.Sprint(field.Interface())})
+	}
+	return meta
+}
+
+// loadTemplates parses the built-in theme, then any overrides from dir
+func loadTemplates(dir string) (*template.Template, error) {
+	templates, err := template.New("").Funcs(template.FuncMap{
+		"metaTags": metaTags,
+		"date": func(value string) string {
+			return parseDBTime(value).Format("January 2, 2006")
+		},
+	}).ParseFS(defaultTemplates, "templates/*.html")
+	if err != nil {
+		return nil, err
+	}
+	if dir == "" {
+		return templates, nil
+	}
+
+	overrides, err := filepath.Glob(filepath.Join(dir, "*.html"))
+	if err != nil {
+		return nil, err
+	}
+	if len(overrides) == 0 {
+		log.Printf("⚠️ Warning: no templates found in %s, using the built-in theme", dir)
+		return templates, nil
+	}
+	return templates.ParseFiles(overrides...)
+}
+
+// renderPage executes a template into a buffer first, so a failing template
+// doesn't leave a half-written page behind
+func renderPage(w http.ResponseWriter, status int, name string, data interface{}) {
+	var buf bytes.Buffer
+	if err := pageTemplates.ExecuteTemplate(&buf, name, data); err != nil {
+		log.Printf("Failed to render %s: %v", name, err)
+		http.Error(w, "Could not render page", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	buf.WriteTo(w)
+}
+
+// postPageHandler renders a published blog post as HTML
+// @Summary Blog post page
+// @Description Server-rendered HTML page of a published blog post, with its SEO metadata in the head.
+// @Description Only available when HTML_PAGES is enabled.
+// @Tags pages
+// @Produce html
+// @Param urlKeyword path string true "URL Keyword of the blog post"
+// @Success 200 {string} string "HTML page"
+// @Success 301 "Moved permanently to the post's current URL keyword"
+// @Failure 404 {string} string "Not found"
+// @Router /posts/{urlKeyword} [get]
+func postPageHandler(w http.ResponseWriter, r *http.Request) {
+	urlKeyword := strings.TrimPrefix(r.URL.Path, "/posts/")
+	if urlKeyword == "" {
+		http.Redirect(w, r, "/posts", http.StatusMovedPermanently)
+		return
+	}
+
+	post, err := getBlogPost(db, urlKeyword)
+	if err == nil && post.Status != StatusPublished {
+		err = sql.ErrNoRows
+	}
+	if err == sql.ErrNoRows {
+		if target, ok := resolveRedirect(urlKeyword); ok {
+			redirectToSlug(w, r, "/posts/", target)
+			return
+		}
+		http.NotFound(w, r)
+		return
+	} else if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+
+	og := buildOpenGraph(post)
+	card := buildTwitterCard(post)
+	renderPage(w, http.StatusOK, "post.html", PostPage{
+		Head: PageHead{
+			Title:       post.Title + " | " + site.Name,
+			Description: post.MetaDescription,
+			Canonical:   postURL(post.UrlKeyword),
+			JSONLD:      buildSEOData(post),
+			OpenGraph:   &og,
+			TwitterCard: &card,
+		},
+		Site:    site,
+		Post:    post,
+		Content: template.HTML(post.DescriptionHTML),
+	})
+}
+
+// postIndexHandler renders a paginated HTML index of published posts
+// @Summary Blog index page
+// @Description Server-rendered HTML index of published blog posts, newest first. Accepts the same filters as /blogs.
+// @Description Only available when HTML_PAGES is enabled.
+// @Tags pages
+// @Produce html
+// @Param page query int false "Page number"
+// @Param tags query string false "Comma-separated tags to filter by"
+// @Param topic query string false "Topic"
+// @Param service query string false "Service"
+// @Param industry query string false "Industry"
+// @Param author query string false "Slug of the author"
+// @Success 200 {string} string "HTML page"
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Not found"
+// @Router /posts [get]
+func postIndexHandler(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	filter, err := parsePostFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// The HTML pages are public, whoever is asking
+	filter.authenticated = false
+	filter.Status = ""
+	where, args := filter.where()
+
+	var totalPosts int
+	if err := db.QueryRow("SELECT COUNT(*) FROM blog_posts WHERE "+where, args...).Scan(&totalPosts); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	totalPages := (totalPosts + htmlPageSize - 1) / htmlPageSize
+	if page > 1 && page > totalPages {
+		http.NotFound(w, r)
+		return
+	}
+
+	rows, err := db.Query("SELECT "+blogPostColumns+" FROM blog_posts WHERE "+where+
+		" ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?", append(args, htmlPageSize, (page-1)*htmlPageSize)...)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	posts := []BlogPost{}
+	for rows.Next() {
+		post, err := scanBlogPost(rows)
+		if err != nil {
+			continue
+		}
+		posts = append(posts, post)
+	}
+
+	// Page links keep the filters, and page 1 is the bare listing
+	pageURL := func(n int) string {
+		q := r.URL.Query()
+		q.Del("page")
+		if n > 1 {
+			q.Set("page", strconv.Itoa(n))
+		}
+		if len(q) == 0 {
+			return absoluteURL("/posts")
+		}
+		return absoluteURL("/posts?" + q.Encode())
+	}
+
+	heading := "Latest posts"
+	switch {
+	case len(filter.Tags) > 0:
+		heading = "Posts tagged " + strings.Join(filter.Tags, ", ")
+	case filter.Topic != "":
+		heading = filter.Topic
+	case filter.Service != "":
+		heading = filter.Service
+	case filter.Industry != "":
+		heading = filter.Industry
+	}
+
+	var author *Author
+	if filter.Author != "" {
+		found, err := getAuthor(db, filter.Author)
+		if err == sql.ErrNoRows {
+			http.NotFound(w, r)
+			return
+		} else if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Database error", http.StatusInternalServerError)
+			return
+		}
+		author = &found
+		heading = "Posts by " + author.Name
+	}
+
+	head := PageHead{
+		Title:       heading + " | " + site.Name,
+		Description: heading + " from " + site.Name,
+		Canonical:   pageURL(page),
+	}
+	if page > 1 {
+		head.Title = fmt.Sprintf("%s, page %d | %s", heading, page, site.Name)
+		head.PrevURL = pageURL(page - 1)
+	}
+	if page < totalPages {
+		head.NextURL = pageURL(page + 1)
+	}
+
+	renderPage(w, http.StatusOK, "index.html", IndexPage{
+		Head:       head,
+		Site:       site,
+		Heading:    heading,
+		Posts:      posts,
+		Page:       page,
+		TotalPages: totalPages,
+		Author:     author,
+	})
+}
@@ -0,0 +1,486 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default limits, generous enough for an editor working in a browser
+const (
+	defaultReadLimit     = "300/m"
+	defaultWriteLimit    = "60/m"
+	defaultUploadLimit   = "100/h"
+	defaultUploadQuotaMB = 200
+
+	// Failed logins and bad tokens allowed per client IP
+	defaultAuthFailureLimit = "10/m"
+)
+
+// rateLimit allows a number of requests per period, all of which may be
+// made at once
+type rateLimit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// RateLimiter throttles each client, identified by its API key or user, or
+// by its IP address when anonymous. Reads and writes are limited
+// separately, and every uploaded file also takes from an upload limit and
+// counts towards a daily byte quota. Failed authentication attempts are
+// limited per IP address, so tokens and passwords can't be guessed at
+// speed. It is configured from the environment:
+//
+//	RATE_LIMIT_READ=300/m
+//	RATE_LIMIT_WRITE=60/m
+//	RATE_LIMIT_UPLOAD=100/h
+//	RATE_LIMIT_AUTH_FAILURES=10/m
+//	UPLOAD_QUOTA_MB=200
+//	TRUSTED_PROXIES=10.0.0.0/8,127.0.0.1
+//
+// A limit of "off" disables it, as does a quota of 0.
+type RateLimiter struct {
+	read, write, upload, authFailures *tokenBuckets
+
+	// Bytes a client may upload per UTC day, 0 for no quota
+	UploadQuota int64
+
+	// Proxies whose X-Forwarded-For header is believed
+	TrustedProxies []netip.Prefix
+}
+
+// loadRateLimiter reads the limits from the environment and starts
+// forgetting idle clients in the background
+func loadRateLimiter() *RateLimiter {
+	limiter := &RateLimiter{
+		read:         newTokenBuckets("RATE_LIMIT_READ", defaultReadLimit),
+		write:        newTokenBuckets("RATE_LIMIT_WRITE", defaultWriteLimit),
+		upload:       newTokenBuckets("RATE_LIMIT_UPLOAD", defaultUploadLimit),
+		authFailures: newTokenBuckets("RATE_LIMIT_AUTH_FAILURES", defaultAuthFailureLimit),
+		UploadQuota:  defaultUploadQuotaMB << 20,
+	}
+
+	if raw := os.Getenv("UPLOAD_QUOTA_MB"); raw != "" {
+		mb, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || mb < 0 {
+			log.Fatalf("❌ UPLOAD_QUOTA_MB must be a number of megabytes, got %q", raw)
+		}
+		limiter.UploadQuota = mb << 20
+	}
+
+	for _, proxy := range envList("TRUSTED_PROXIES", nil) {
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(proxy)
+			if addrErr != nil {
+				log.Fatalf("❌ TRUSTED_PROXIES entries must be IP addresses or CIDR ranges, got %q", proxy)
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		limiter.TrustedProxies = append(limiter.TrustedProxies, prefix.Masked())
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			limiter.sweep(now)
+		}
+	}()
+
+	return limiter
+}
+
+// parseRateLimit parses a limit like 60/m or 1000/24h
+func parseRateLimit(raw string) (rateLimit, error) {
+	count, period, ok := strings.Cut(raw, "/")
+	requests, err := strconv.Atoi(strings.TrimSpace(count))
+	if !ok || err != nil || requests <= 0 {
+		return rateLimit{}, fmt.Errorf("expected requests/period, like 60/m")
+	}
+	period = strings.TrimSpace(period)
+	if period != "" && (period[0] < '0' || period[0] > '9') {
+		period = "1" + period
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return rateLimit{}, fmt.Errorf("expected requests/period, like 60/m")
+	}
+	return rateLimit{Requests: requests, Period: d}, nil
+}
+
+// tokenBuckets holds a bucket per client. Each bucket holds up to
+// Requests tokens and refills at Requests per Period; a request takes one.
+// A nil *tokenBuckets allows everything.
+type tokenBuckets struct {
+	limit rateLimit
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// newTokenBuckets reads a limit from the environment, returning nil if it
+// is turned off
+func newTokenBuckets(name, fallback string) *tokenBuckets {
+	raw := os.Getenv(name)
+	if raw == "" {
+		raw = fallback
+	}
+	if strings.EqualFold(raw, "off") {
+		return nil
+	}
+	limit, err := parseRateLimit(raw)
+	if err != nil {
+		log.Fatalf("❌ Invalid %s %q: %v", name, raw, err)
+	}
+	return &tokenBuckets{limit: limit, buckets: map[string]*tokenBucket{}}
+}
+
+// take removes a token from the client's bucket, reporting the tokens left
+// or, when the bucket is empty, how long until the next one
+func (b *tokenBuckets) take(client string, now time.Time) (int, time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bucket, wait := b.refill(client, now)
+	if wait > 0 {
+		return 0, wait, false
+	}
+	bucket.tokens--
+	return int(bucket.tokens), 0, true
+}
+
+// check reports whether the client's bucket has a token left without
+// taking it, or else how long until it will
+func (b *tokenBuckets) check(client string, now time.Time) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_, wait := b.refill(client, now)
+	return wait, wait == 0
+}
+
+// refill tops up the client's bucket for the time passed since it was last
+// used, returning it and how long until it holds a whole token. The caller
+// must hold b.mu.
+func (b *tokenBuckets) refill(client string, now time.Time) (*tokenBucket, time.Duration) {
+	capacity := float64(b.limit.Requests)
+	interval := b.limit.Period / time.Duration(b.limit.Requests)
+
+	bucket, ok := b.buckets[client]
+	if !ok {
+		bucket = &tokenBucket{tokens: capacity, updated: now}
+		b.buckets[client] = bucket
+	}
+	bucket.tokens = min(capacity, bucket.tokens+float64(now.Sub(bucket.updated))/float64(interval))
+	bucket.updated = now
+
+	if bucket.tokens < 1 {
+		return bucket, time.Duration((1 - bucket.tokens) * float64(interval))
+	}
+	return bucket, 0
+}
+
+// sweep forgets clients whose buckets have refilled, since they are no
+// different from clients never seen
+func (b *tokenBuckets) sweep(now time.Time) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for client, bucket := range b.buckets {
+		if now.Sub(bucket.updated) >= b.limit.Period {
+			delete(b.buckets, client)
+		}
+	}
+}
+
+// sweep forgets idle clients and upload usage of past days
+func (l *RateLimiter) sweep(now time.Time) {
+	l.read.sweep(now)
+	l.write.sweep(now)
+	l.upload.sweep(now)
+	l.authFailures.sweep(now)
+
+	if _, err := db.Exec("DELETE FROM upload_usage WHERE day < ?", uploadDay(now)); err != nil {
+		log.Println("❌ Failed to clean up upload usage:", err)
+	}
+}
+
+type authFailureKey struct{}
+
+// Guard turns away clients that failed to authenticate too often, before
+// their credentials are looked up. Only requests that may carry credentials
+// are turned away, so a blocked address can still read the public site. It
+// must run before authMiddleware, and handlers that check credentials
+// report failures through authFailed.
+func (l *RateLimiter) Guard(next http.HandlerFunc) http.HandlerFunc {
+	if l.authFailures == nil {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		client := l.ipKey(r)
+		anonymousRead := r.Header.Get("Authorization") == "" &&
+			(r.Method == http.MethodGet || r.Method == http.MethodHead)
+		if wait, ok := l.authFailures.check(client, time.Now()); !ok && !anonymousRead {
+			tooManyRequests(w, wait, "Too many failed authentication attempts, try again later")
+			return
+		}
+
+		failed := func() { l.authFailures.take(client, time.Now()) }
+		next(w, r.WithContext(context.WithValue(r.Context(), authFailureKey{}, failed)))
+	}
+}
+
+// authFailed counts a rejected token or password against the client
+func authFailed(r *http.Request) {
+	if failed, ok := r.Context().Value(authFailureKey{}).(func()); ok {
+		failed()
+	}
+}
+
+// Handler throttles requests before passing them on, answering 429 with a
+// Retry-After header once a client is over its limit. It must run after
+// authMiddleware to tell API keys and users apart. Uploads are charged by
+// validateAndSaveFile, when a file is actually saved.
+func (l *RateLimiter) Handler(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		client := l.clientKey(r)
+
+		buckets := l.write
+		if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+			buckets = l.read
+		}
+
+		if buckets != nil {
+			remaining, wait, ok := buckets.take(client, time.Now())
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(buckets.limit.Requests))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+			if !ok {
+				tooManyRequests(w, wait, "Rate limit exceeded, try again later")
+				return
+			}
+		}
+
+		r = r.WithContext(context.WithValue(r.Context(), uploadClientKey{}, uploadClient{l, client}))
+		next(w, r)
+	}
+}
+
+type uploadClientKey struct{}
+
+// uploadClient is who the uploads of a request are charged to
+type uploadClient struct {
+	limiter *RateLimiter
+	client  string
+}
+
+// rateLimitError reports that a client is over its upload limit or quota
+type rateLimitError struct {
+	message string
+	wait    time.Duration
+}
+
+func (e *rateLimitError) Error() string {
+	return e.message
+}
+
+// checkUpload takes an upload from the client's upload limit and checks
+// that size more bytes fit in its daily quota, reporting a *rateLimitError
+// if either is used up. Nothing is counted against the quota until the file
+// is saved and chargeUpload is called.
+func checkUpload(r *http.Request, size int64) error {
+	uploader, ok := r.Context().Value(uploadClientKey{}).(uploadClient)
+	if !ok {
+		return nil
+	}
+	l := uploader.limiter
+
+	now := time.Now().UTC()
+	if l.upload != nil {
+		if _, wait, ok := l.upload.take(uploader.client, now); !ok {
+			return &rateLimitError{message: "Upload rate limit exceeded, try again later", wait: wait}
+		}
+	}
+	if l.UploadQuota == 0 {
+		return nil
+	}
+
+	used, err := uploadUsage(uploader.client, uploadDay(now))
+	if err != nil {
+		return err
+	}
+	if used+size > l.UploadQuota {
+		return quotaExceeded(now)
+	}
+	return nil
+}
+
+// chargeUpload counts size saved bytes against the client's daily quota.
+// The check and the update are one statement, so concurrent uploads can't
+// both squeeze under the quota; the one that doesn't fit gets a
+// *rateLimitError.
+func chargeUpload(r *http.Request, size int64) error {
+	uploader, ok := r.Context().Value(uploadClientKey{}).(uploadClient)
+	if !ok || uploader.limiter.UploadQuota == 0 || size == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	charged, err := recordUpload(uploader.client, uploadDay(now), size, uploader.limiter.UploadQuota)
+	if err != nil {
+		return err
+	}
+	if !charged {
+		return quotaExceeded(now)
+	}
+	return nil
+}
+
+// refundUpload gives back quota charged for a file that was not kept
+func refundUpload(r *http.Request, size int64) {
+	uploader, ok := r.Context().Value(uploadClientKey{}).(uploadClient)
+	if !ok || uploader.limiter.UploadQuota == 0 || size == 0 {
+		return
+	}
+
+	_, err := db.Exec("UPDATE upload_usage SET bytes = MAX(bytes - ?, 0) WHERE client = ? AND day = ?",
+		size, uploader.client, uploadDay(time.Now()))
+	if err != nil {
+		log.Printf("Failed to refund upload quota for %s: %v", uploader.client, err)
+	}
+}
+
+// quotaExceeded reports an exhausted daily upload quota, which frees up at
+// the next UTC midnight
+func quotaExceeded(now time.Time) error {
+	midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	return &rateLimitError{message: "Daily upload quota exceeded", wait: midnight.Sub(now)}
+}
+
+// writeUploadError answers a request whose upload failed: 429 if the client
+// is over its upload limit or quota, 400 otherwise
+func writeUploadError(w http.ResponseWriter, err error, prefix string) {
+	var limited *rateLimitError
+	if errors.As(err, &limited) {
+		tooManyRequests(w, limited.wait, limited.message)
+		return
+	}
+	writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("%s: %v", prefix, err))
+}
+
+// tooManyRequests rejects a request, telling the client when to retry
+func tooManyRequests(w http.ResponseWriter, wait time.Duration, message string) {
+	seconds := max(1, int(math.Ceil(wait.Seconds())))
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	writeErrorResponse(w, http.StatusTooManyRequests, message)
+}
+
+// clientKey identifies who a request counts against: the API key or user
+// it was made with, or else the client's address
+func (l *RateLimiter) clientKey(r *http.Request) string {
+	principal := principalFrom(r)
+	switch {
+	case principal == nil:
+		return l.ipKey(r)
+	case principal.KeyID != 0:
+		return fmt.Sprintf("key:%d", principal.KeyID)
+	case principal.UserID != 0:
+		return fmt.Sprintf("user:%d", principal.UserID)
+	default:
+		return "admin"
+	}
+}
+
+// ipKey identifies a client by its address. IPv6 clients are grouped by
+// /64, as each is usually handed a whole one.
+func (l *RateLimiter) ipKey(r *http.Request) string {
+	ip := l.clientIP(r)
+	if ip.Is6() {
+		prefix, _ := ip.Prefix(64)
+		return "ip:" + prefix.String()
+	}
+	return "ip:" + ip.String()
+}
+
+// clientIP returns the address of the client. X-Forwarded-For is only
+// believed when the request comes from a trusted proxy, and then read from
+// the nearest hop back to the first address that isn't a trusted proxy,
+// since anything before that may have been made up by the client.
+func (l *RateLimiter) clientIP(r *http.Request) netip.Addr {
+	addrPort, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil {
+		return netip.Addr{}
+	}
+	ip := addrPort.Addr().Unmap()
+	if !l.trusted(ip) {
+		return ip
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		ip = hop.Unmap()
+		if !l.trusted(ip) {
+			break
+		}
+	}
+	return ip
+}
+
+// trusted reports whether an address belongs to a trusted proxy
+func (l *RateLimiter) trusted(ip netip.Addr) bool {
+	for _, prefix := range l.TrustedProxies {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// uploadDay names the UTC day upload quotas are counted for
+func uploadDay(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// uploadUsage returns the bytes a client has uploaded on a day
+func uploadUsage(client, day string) (int64, error) {
+	var used int64
+	err := db.QueryRow("SELECT bytes FROM upload_usage WHERE client = ? AND day = ?", client, day).Scan(&used)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return used, err
+}
+
+// recordUpload adds to the bytes a client has uploaded on a day, unless that
+// would take it over quota, and reports whether it did
+func recordUpload(client, day string, n, quota int64) (bool, error) {
+	result, err := db.Exec(`INSERT INTO upload_usage (client, day, bytes) SELECT ?, ?, ? WHERE ? <= ?
+		ON CONFLICT (client, day) DO UPDATE SET bytes = bytes + excluded.bytes
+		WHERE bytes + excluded.bytes <= ?`, client, day, n, n, quota, quota)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
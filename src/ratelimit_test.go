@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestUploadQuota(t *testing.T) {
+	type step struct {
+		op      string // check, charge or refund
+		size    int64
+		limited bool
+	}
+	tests := []struct {
+		name  string
+		quota int64
+		steps []step
+		used  int64
+	}{
+		{
+			name:  "charges saved bytes",
+			quota: 100,
+			steps: []step{{"check", 40, false}, {"charge", 40, false}, {"charge", 60, false}},
+			used:  100,
+		},
+		{
+			name:  "check does not charge",
+			quota: 100,
+			steps: []step{{"check", 80, false}, {"check", 80, false}},
+			used:  0,
+		},
+		{
+			name:  "check rejects what would not fit",
+			quota: 100,
+			steps: []step{{"charge", 70, false}, {"check", 31, true}, {"check", 30, false}},
+			used:  70,
+		},
+		{
+			name:  "charge rejects what no longer fits",
+			quota: 100,
+			steps: []step{{"check", 60, false}, {"check", 60, false}, {"charge", 60, false}, {"charge", 60, true}},
+			used:  60,
+		},
+		{
+			name:  "first upload over quota",
+			quota: 100,
+			steps: []step{{"check", 101, true}, {"charge", 101, true}},
+			used:  0,
+		},
+		{
+			name:  "refund frees quota",
+			quota: 100,
+			steps: []step{{"charge", 90, false}, {"refund", 90, false}, {"charge", 100, false}},
+			used:  100,
+		},
+		{
+			name:  "refund never goes negative",
+			quota: 100,
+			steps: []step{{"charge", 10, false}, {"refund", 50, false}},
+			used:  0,
+		},
+		{
+			name:  "no quota",
+			quota: 0,
+			steps: []step{{"check", 1 << 40, false}, {"charge", 1 << 40, false}},
+			used:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			openTestDB(t)
+			limiter := &RateLimiter{UploadQuota: tt.quota}
+			r := httptest.NewRequest("POST", "/blog", nil)
+			r = r.WithContext(context.WithValue(r.Context(), uploadClientKey{}, uploadClient{limiter, "key:1"}))
+
+			for i, s := range tt.steps {
+				var err error
+				switch s.op {
+				case "check":
+					err = checkUpload(r, s.size)
+				case "charge":
+					err = chargeUpload(r, s.size)
+				case "refund":
+					refundUpload(r, s.size)
+				}
+
+				var limited *rateLimitError
+				if errors.As(err, &limited) != s.limited {
+					t.Fatalf("step %d: %s(%d) = %v, want limited %v", i, s.op, s.size, err, s.limited)
+				} else if err != nil && !s.limited {
+					t.Fatalf("step %d: %s(%d): %v", i, s.op, s.size, err)
+				}
+			}
+
+			used, err := uploadUsage("key:1", uploadDay(time.Now()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if used != tt.used {
+				t.Errorf("used %d bytes, want %d", used, tt.used)
+			}
+		})
+	}
+}
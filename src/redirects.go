@@ -0,0 +1,75 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+)
+
+// maxRedirectHops bounds how far resolveRedirect follows a chain. Chains are
+// collapsed when written, so more than one hop only happens with legacy data.
+const maxRedirectHops = 10
+
+// claimSlug drops any redirect away from slug because a post is about to
+// use it again. Left in place, the redirect would shadow the live post and
+// could close a loop with later slug changes.
+func claimSlug(tx *sql.Tx, slug string) error {
+	_, err := tx.Exec("DELETE FROM slug_redirects WHERE old_slug = ?", slug)
+	return err
+}
+
+// recordSlugChange remembers that a post moved from oldSlug to newSlug.
+// Existing redirects to oldSlug are repointed at newSlug so chains never
+// build up, and newSlug is claimed since it is live again.
+func recordSlugChange(tx *sql.Tx, oldSlug, newSlug string) error {
+	if err := claimSlug(tx, newSlug); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("UPDATE slug_redirects SET new_slug = ? WHERE new_slug = ?", newSlug, oldSlug); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`
+	INSERT INTO slug_redirects (old_slug, new_slug) VALUES (?, ?)
+	ON CONFLICT(old_slug) DO UPDATE SET new_slug = excluded.new_slug, created_at = CURRENT_TIMESTAMP`,
+		oldSlug, newSlug)
+	return err
+}
+
+// resolveRedirect follows the redirects recorded for a retired slug to the
+// slug currently in use. It reports false when there is no redirect or the
+// chain loops back on itself.
+func resolveRedirect(slug string) (string, bool) {
+	visited := map[string]bool{slug: true}
+	current := slug
+
+	for hop := 0; hop < maxRedirectHops; hop++ {
+		var next string
+		err := db.QueryRow("SELECT new_slug FROM slug_redirects WHERE old_slug = ?", current).Scan(&next)
+		if err == sql.ErrNoRows {
+			return current, current != slug
+		} else if err != nil {
+			log.Printf("Failed to resolve redirect for %s: %v", slug, err)
+			return "", false
+		}
+
+		if visited[next] {
+			log.Printf("Redirect loop detected for %s", slug)
+			return "", false
+		}
+		visited[next] = true
+		current = next
+	}
+
+	log.Printf("Redirect chain too long for %s", slug)
+	return "", false
+}
+
+// redirectToSlug answers with a permanent redirect to a post's current URL
+// under prefix, keeping the original query string
+func redirectToSlug(w http.ResponseWriter, r *http.Request, prefix, slug string) {
+	target := prefix + slug
+	if r.URL.RawQuery != "" {
+		target += "?" + r.URL.RawQuery
+	}
+	http.Redirect(w, r, target, http.StatusMovedPermanently)
+}
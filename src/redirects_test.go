@@ -0,0 +1,107 @@
+package main
+
+import (
+	"database/sql"
+	"testing"
+)
+
+// openTestDB points the global db at a fresh in-memory database with the
+// full schema, restoring the previous one when the test ends
+func openTestDB(t *testing.T) {
+	t.Helper()
+	testDB, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every connection to :memory: is a separate database, so keep one
+	testDB.SetMaxOpenConns(1)
+
+	previous := db
+	db = testDB
+	t.Cleanup(func() {
+		testDB.Close()
+		db = previous
+	})
+	createTables()
+}
+
+func TestRecordSlugChange(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes [][2]string
+		resolve map[string]string
+	}{
+		{
+			name:    "single rename",
+			changes: [][2]string{{"a", "b"}},
+			resolve: map[string]string{"a": "b", "b": ""},
+		},
+		{
+			name:    "chain collapses to the latest slug",
+			changes: [][2]string{{"a", "b"}, {"b", "c"}, {"c", "d"}},
+			resolve: map[string]string{"a": "d", "b": "d", "c": "d", "d": ""},
+		},
+		{
+			name:    "renaming back drops the loop",
+			changes: [][2]string{{"a", "b"}, {"b", "a"}},
+			resolve: map[string]string{"a": "", "b": "a"},
+		},
+		{
+			name:    "returning to an older slug keeps the others pointed at it",
+			changes: [][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}},
+			resolve: map[string]string{"a": "", "b": "a", "c": "a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			openTestDB(t)
+			for _, change := range tt.changes {
+				err := withTransaction(func(tx *sql.Tx) error {
+					return recordSlugChange(tx, change[0], change[1])
+				})
+				if err != nil {
+					t.Fatalf("recordSlugChange(%q, %q): %v", change[0], change[1], err)
+				}
+			}
+
+			for slug, want := range tt.resolve {
+				got, ok := resolveRedirect(slug)
+				if want == "" {
+					if ok {
+						t.Errorf("resolveRedirect(%q) = %q, want no redirect", slug, got)
+					}
+				} else if !ok || got != want {
+					t.Errorf("resolveRedirect(%q) = %q, %v, want %q", slug, got, ok, want)
+				}
+			}
+
+			var hops int
+			if err := db.QueryRow(`
+			SELECT COUNT(*) FROM slug_redirects r
+			JOIN slug_redirects next ON next.old_slug = r.new_slug`).Scan(&hops); err != nil {
+				t.Fatal(err)
+			}
+			if hops != 0 {
+				t.Errorf("found %d redirects pointing at another redirect", hops)
+			}
+		})
+	}
+}
+
+func TestClaimSlug(t *testing.T) {
+	openTestDB(t)
+	err := withTransaction(func(tx *sql.Tx) error {
+		if err := recordSlugChange(tx, "old", "new"); err != nil {
+			return err
+		}
+		return claimSlug(tx, "old")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, ok := resolveRedirect("old"); ok {
+		t.Errorf("resolveRedirect(%q) = %q after the slug was claimed, want no redirect", "old", got)
+	}
+}
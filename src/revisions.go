@@ -0,0 +1,391 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Revision is a snapshot of a blog post taken after each change
+// @swagger:model
+type Revision struct {
+	ID              int64    `json:"id"`
+	PostID          int64    `json:"post_id"`
+	Revision        int      `json:"revision"`
+	Title           string   `json:"title"`
+	MetaDescription string   `json:"meta_description"`
+	FocusKeyword    string   `json:"focus_keyword"`
+	UrlKeyword      string   `json:"url_keyword"`
+	Image           string   `json:"image"`
+	Tags            []string `json:"tags"`
+	Topic           string   `json:"topic"`
+	Service         string   `json:"service"`
+	Industry        string   `json:"industry"`
+	Priority        string   `json:"priority"`
+	Status          string   `json:"status"`
+	PublishAt       *string  `json:"publish_at,omitempty"`
+	AuthorID        *int64   `json:"author_id,omitempty"`
+	Description     string   `json:"description_markdown"`
+	CreatedAt       string   `json:"created_at"`
+}
+
+// FieldChange describes how a single field differs between two revisions
+// @swagger:model
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// RevisionDiff is the field-level difference between two revisions
+// @swagger:model
+type RevisionDiff struct {
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// revisionSnapshotQuery copies blog_posts rows into post_revisions, numbering
+// each snapshot after the post's latest revision. Callers append a WHERE clause.
+const revisionSnapshotQuery = `
+	INSERT INTO post_revisions (
+		post_id, revision, title, meta_description, focus_keyword, url_keyword,
+		image, tags, topic, service, industry, priority, status, publish_at,
+		author_id, description
+	)
+	SELECT
+		id,
+		COALESCE((SELECT MAX(revision) FROM post_revisions WHERE post_id = blog_posts.id), 0) + 1,
+		title, meta_description, focus_keyword, url_keyword,
+		image, ` + postTagsJSON + `, topic, service, industry, priority, status, publish_at,
+		author_id, description
+	FROM blog_posts`
+
+const revisionColumns = `id, post_id, revision, title, meta_description, focus_keyword,
+	url_keyword, image, tags, topic, service, industry, priority, status, publish_at,
+	author_id, description, created_at`
+
+// recordRevision snapshots the current state of a post as its next revision
+func recordRevision(tx *sql.Tx, postID int64) error {
+	_, err := tx.Exec(revisionSnapshotQuery+" WHERE id = ?", postID)
+	return err
+}
+
+func scanRevision(row rowScanner) (Revision, error) {
+	var tagsJSON string
+	var rev Revision
+	err := row.Scan(
+		&rev.ID, &rev.PostID, &rev.Revision, &rev.Title, &rev.MetaDescription,
+		&rev.FocusKeyword, &rev.UrlKeyword, &rev.Image, &tagsJSON, &rev.Topic,
+		&rev.Service, &rev.Industry, &rev.Priority, &rev.Status, &rev.PublishAt,
+		&rev.AuthorID, &rev.Description, &rev.CreatedAt,
+	)
+	if err != nil {
+		return rev, err
+	}
+
+	if tagsJSON != "" {
+		if err := json.Unmarshal([]byte(tagsJSON), &rev.Tags); err != nil {
+			fmt.Println("Error unmarshaling tags:", err)
+			rev.Tags = []string{}
+		}
+	}
+
+	return rev, nil
+}
+
+// getRevision fetches a single revision of a post by its number
+func getRevision(q queryer, postID int64, number int) (Revision, error) {
+	return scanRevision(q.QueryRow(
+		"SELECT "+revisionColumns+" FROM post_revisions WHERE post_id = ? AND revision = ?",
+		postID, number))
+}
+
+// diffFields lists the comparable fields of a revision in display order
+func (rev Revision) diffFields() []FieldChange {
+	publishAt := ""
+	if rev.PublishAt != nil {
+		publishAt = *rev.PublishAt
+	}
+	var authorID interface{}
+	if rev.AuthorID != nil {
+		authorID = *rev.AuthorID
+	}
+	tags := rev.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return []FieldChange{
+		{Field: "title", To: rev.Title},
+		{Field: "meta_description", To: rev.MetaDescription},
+		{Field: "focus_keyword", To: rev.FocusKeyword},
+		{Field: "url_keyword", To: rev.UrlKeyword},
+		{Field: "image", To: rev.Image},
+		{Field: "tags", To: tags},
+		{Field: "topic", To: rev.Topic},
+		{Field: "service", To: rev.Service},
+		{Field: "industry", To: rev.Industry},
+		{Field: "priority", To: rev.Priority},
+		{Field: "status", To: rev.Status},
+		{Field: "publish_at", To: publishAt},
+		{Field: "author_id", To: authorID},
+		{Field: "description_markdown", To: rev.Description},
+	}
+}
+
+// diffRevisions returns the fields that differ between two revisions
+func diffRevisions(from, to Revision) []FieldChange {
+	changes := []FieldChange{}
+	before := from.diffFields()
+	for i, after := range to.diffFields() {
+		if fmt.Sprint(before[i].To) != fmt.Sprint(after.To) {
+			changes = append(changes, FieldChange{
+				Field: after.Field,
+				From:  before[i].To,
+				To:    after.To,
+			})
+		}
+	}
+	return changes
+}
+
+// revisionsRouter dispatches /blog/{urlKeyword}/revisions requests
+func revisionsRouter(w http.ResponseWriter, r *http.Request) {
+	_, action := blogPathParts(r)
+	sub := strings.TrimPrefix(strings.TrimPrefix(action, "revisions"), "/")
+
+	switch {
+	case sub == "" || sub == "diff":
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", "GET")
+			writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		if sub == "" {
+			requireRole(RoleViewer, listRevisionsHandler)(w, r)
+		} else {
+			requireRole(RoleViewer, diffRevisionsHandler)(w, r)
+		}
+	case strings.HasSuffix(sub, "/rollback"):
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		requireRole(RoleEditor, rollbackRevisionHandler)(w, r)
+	default:
+		writeErrorResponse(w, http.StatusNotFound, "Not found")
+	}
+}
+
+// listRevisionsHandler lists every revision of a blog post
+// @Summary List revisions of a blog post
+// @Description Get every stored revision of a blog post, newest first
+// @Tags revisions
+// @Produce json
+// @Security BearerAuth
+// @Param urlKeyword path string true "URL Keyword of the blog post"
+// @Success 200 {array} Revision
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /blog/{urlKeyword}/revisions [get]
+func listRevisionsHandler(w http.ResponseWriter, r *http.Request) {
+	urlKeyword, _ := blogPathParts(r)
+
+	post, err := getVisibleBlogPost(r, urlKeyword)
+	if err == sql.ErrNoRows {
+		writeErrorResponse(w, http.StatusNotFound, "Blog post not found")
+		return
+	} else if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	rows, err := db.Query("SELECT "+revisionColumns+" FROM post_revisions WHERE post_id = ? ORDER BY revision DESC", post.ID)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "Could not fetch revisions")
+		return
+	}
+	defer rows.Close()
+
+	revisions := []Revision{}
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			continue
+		}
+		revisions = append(revisions, rev)
+	}
+
+	writeJSONResponse(w, http.StatusOK, revisions)
+}
+
+// diffRevisionsHandler compares two revisions of a blog post field by field
+// @Summary Diff two revisions of a blog post
+// @Description Get the fields that changed between two revisions of a blog post
+// @Tags revisions
+// @Produce json
+// @Security BearerAuth
+// @Param urlKeyword path string true "URL Keyword of the blog post"
+// @Param from query int true "Revision number to compare from"
+// @Param to query int true "Revision number to compare to"
+// @Success 200 {object} RevisionDiff
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /blog/{urlKeyword}/revisions/diff [get]
+func diffRevisionsHandler(w http.ResponseWriter, r *http.Request) {
+	urlKeyword, _ := blogPathParts(r)
+
+	from, errFrom := strconv.Atoi(r.URL.Query().Get("from"))
+	to, errTo := strconv.Atoi(r.URL.Query().Get("to"))
+	if errFrom != nil || errTo != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "from and to must be revision numbers")
+		return
+	}
+
+	post, err := getVisibleBlogPost(r, urlKeyword)
+	if err == sql.ErrNoRows {
+		writeErrorResponse(w, http.StatusNotFound, "Blog post not found")
+		return
+	} else if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	before, err := getRevision(db, post.ID, from)
+	if err == nil {
+		var after Revision
+		after, err = getRevision(db, post.ID, to)
+		if err == nil {
+			writeJSONResponse(w, http.StatusOK, RevisionDiff{
+				From:    from,
+				To:      to,
+				Changes: diffRevisions(before, after),
+			})
+			return
+		}
+	}
+
+	if err == sql.ErrNoRows {
+		writeErrorResponse(w, http.StatusNotFound, "Revision not found")
+		return
+	}
+	fmt.Println(err)
+	writeErrorResponse(w, http.StatusInternalServerError, "Database error")
+}
+
+var errSlugTaken = errors.New("url_keyword of this revision is now used by another post")
+
+// rollbackRevisionHandler restores the content of an old revision
+// @Summary Roll back a blog post to an earlier revision
+// @Description Restore the content of an earlier revision. The restored content is saved as a new revision;
+// @Description the post's workflow status is left unchanged.
+// @Tags revisions
+// @Produce json
+// @Security BearerAuth
+// @Param urlKeyword path string true "URL Keyword of the blog post"
+// @Param revision path int true "Revision number to restore"
+// @Success 200 {object} BlogPost
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /blog/{urlKeyword}/revisions/{revision}/rollback [post]
+func rollbackRevisionHandler(w http.ResponseWriter, r *http.Request) {
+	urlKeyword, action := blogPathParts(r)
+
+	number, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(action, "revisions/"), "/rollback"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "revision must be a number")
+		return
+	}
+
+	var updated BlogPost
+	err = withTransaction(func(tx *sql.Tx) error {
+		post, err := getBlogPost(tx, urlKeyword)
+		if err != nil {
+			return err
+		}
+		rev, err := getRevision(tx, post.ID, number)
+		if err != nil {
+			return err
+		}
+
+		var taken bool
+		err = tx.QueryRow("SELECT EXISTS(SELECT 1 FROM blog_posts WHERE url_keyword = ? AND id != ?)",
+			rev.UrlKeyword, post.ID).Scan(&taken)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return errSlugTaken
+		}
+		descriptionHTML, err := renderMarkdown(rev.Description)
+		if err != nil {
+			return err
+		}
+
+		if rev.UrlKeyword != post.UrlKeyword {
+			if err := recordSlugChange(tx, post.UrlKeyword, rev.UrlKeyword); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec(`
+        UPDATE blog_posts SET
+            title = ?, meta_description = ?, focus_keyword = ?, url_keyword = ?,
+            image = ?, topic = ?, service = ?, industry = ?, priority = ?,
+            author_id = (SELECT id FROM authors WHERE id = ?),
+            description = ?, description_html = ?, updated_at = CURRENT_TIMESTAMP
+        WHERE id = ?`,
+			rev.Title, rev.MetaDescription, rev.FocusKeyword, rev.UrlKeyword,
+			rev.Image, rev.Topic, rev.Service, rev.Industry,
+			rev.Priority, rev.AuthorID, rev.Description, descriptionHTML, post.ID,
+		)
+		if err != nil {
+			return err
+		}
+		if err := setPostTags(tx, post.ID, rev.Tags); err != nil {
+			return err
+		}
+		if err := indexPost(tx, post.ID); err != nil {
+			return err
+		}
+		if err := recordRevision(tx, post.ID); err != nil {
+			return err
+		}
+		updated, err = getBlogPostByID(tx, post.ID)
+		return err
+	})
+
+	if err == sql.ErrNoRows {
+		writeErrorResponse(w, http.StatusNotFound, "Blog post or revision not found")
+		return
+	} else if err == errSlugTaken {
+		writeErrorResponse(w, http.StatusConflict, err.Error())
+		return
+	} else if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to roll back blog post")
+		return
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, updated); err != nil {
+		log.Printf("Failed to write response: %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// sqliteTimeFormat matches CURRENT_TIMESTAMP so stored times compare correctly
+const sqliteTimeFormat = "2006-01-02 15:04:05"
+
+// defaultPublishInterval is how often the publisher runs without PUBLISH_INTERVAL
+const defaultPublishInterval = time.Minute
+
+// parsePublishAt parses an RFC 3339 publish_at form value into the UTC
+// format SQLite compares against CURRENT_TIMESTAMP. An empty value means
+// the post is not scheduled.
+func parsePublishAt(value string) (*string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("publish_at must be an RFC 3339 timestamp, e.g. 2006-01-02T15:04:05Z")
+	}
+	formatted := t.UTC().Format(sqliteTimeFormat)
+	return &formatted, nil
+}
+
+// publishDuePosts publishes every draft whose publish_at has passed and
+// returns how many were published. Because it picks up everything overdue,
+// schedules missed while the server was down are published on the next run.
+func publishDuePosts() (int64, error) {
+	var published int64
+	err := withTransaction(func(tx *sql.Tx) error {
+		rows, err := tx.Query(`
+		SELECT id FROM blog_posts
+		WHERE status = 'draft'
+			AND deleted_at IS NULL
+			AND publish_at IS NOT NULL
+			AND publish_at <= CURRENT_TIMESTAMP`)
+		if err != nil {
+			return err
+		}
+		var due []int64
+		for rows.Next() {
+			var id int64
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			due = append(due, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, id := range due {
+			_, err := tx.Exec(`
+			UPDATE blog_posts SET
+				status = 'published',
+				publish_at = NULL,
+				updated_at = CURRENT_TIMESTAMP
+			WHERE id = ?`, id)
+			if err != nil {
+				return err
+			}
+			if err := recordRevision(tx, id); err != nil {
+				return err
+			}
+		}
+		published = int64(len(due))
+		return nil
+	})
+	return published, err
+}
+
+// startPublisher publishes overdue posts right away, then keeps checking
+// for due posts in a background goroutine every interval
+func startPublisher(interval time.Duration) {
+	publish := func() {
+		count, err := publishDuePosts()
+		if err != nil {
+			log.Println("❌ Scheduled publishing failed:", err)
+			return
+		}
+		if count > 0 {
+			log.Printf("📅 Published %d scheduled post(s)", count)
+		}
+	}
+
+	publish()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			publish()
+		}
+	}()
+}
+
+// samePublishAt reports whether two publish_at values name the same time.
+// Values scanned from the database are RFC 3339 while parsed input is in
+// sqliteTimeFormat, so compare the times rather than the strings.
+func samePublishAt(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	parse := func(value string) time.Time {
+		if t, err := time.Parse(sqliteTimeFormat, value); err == nil {
+			return t
+		}
+		t, _ := time.Parse(time.RFC3339, value)
+		return t
+	}
+	return parse(*a).Equal(parse(*b))
+}
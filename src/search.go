@@ -0,0 +1,261 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// FTS5 is only compiled into go-sqlite3 with a build tag, so build with
+//
+//	go build -tags sqlite_fts5 ./src
+//
+// to enable search. Without it the server still runs and /search answers 503.
+
+// Markers FTS5 puts around matching terms. They are private-use characters,
+// replaced with <mark> tags only after the text has been HTML-escaped.
+const (
+	highlightStart = "\uE000"
+	highlightEnd   = "\uE001"
+)
+
+// searchEnabled reports whether the FTS5 search index could be created
+var searchEnabled bool
+
+// SearchResult is a blog post matching a search, with its rank and highlights
+// @swagger:model
+type SearchResult struct {
+	BlogPost
+
+	// bm25 relevance score; lower is more relevant
+	Score float64 `json:"score"`
+
+	// The HTML-escaped title with matching terms wrapped in <mark>
+	TitleHighlight string `json:"title_highlight"`
+
+	// An HTML-escaped excerpt of the description around the matches, with
+	// terms wrapped in <mark>
+	Snippet string `json:"snippet"`
+}
+
+// SearchResponse represents a paginated list of search results
+// @swagger:model
+type SearchResponse struct {
+	// The search query
+	Query string `json:"query"`
+
+	// Matching blog posts, most relevant first
+	Posts []SearchResult `json:"posts"`
+
+	// Total number of matching blog posts
+	TotalPosts int `json:"totalPosts"`
+
+	// Current page number
+	Page int `json:"page"`
+
+	// Number of items per page
+	PageSize int `json:"pageSize"`
+
+	// Total number of pages
+	TotalPages int `json:"totalPages"`
+}
+
+// searchDocumentQuery selects what gets indexed for a post, tags joined by spaces
+const searchDocumentQuery = `
+	SELECT id, title, meta_description, focus_keyword,
+		COALESCE((SELECT group_concat(t.name, ' ') FROM post_tags pt
+			JOIN tags t ON t.id = pt.tag_id WHERE pt.post_id = blog_posts.id), ''),
+		description
+	FROM blog_posts`
+
+// createSearchIndex creates the FTS5 index and fills it if it is out of
+// step with blog_posts, e.g. on first start or after a restore from backup
+func createSearchIndex() {
+	_, err := db.Exec(`
+	CREATE VIRTUAL TABLE IF NOT EXISTS blog_posts_fts USING fts5(
+		title, meta_description, focus_keyword, tags, description,
+		tokenize = 'porter unicode61'
+	)`)
+	if err != nil && strings.Contains(err.Error(), "no such module") {
+		log.Println("❌ SQLite was built without FTS5, so search is disabled and /search answers 503. Build with -tags sqlite_fts5 to enable it.")
+		return
+	} else if err != nil {
+		log.Fatal("❌ Failed to create search index:", err)
+	}
+	searchEnabled = true
+
+	var indexed, posts int
+	if err := db.QueryRow("SELECT COUNT(*) FROM blog_posts_fts").Scan(&indexed); err != nil {
+		log.Fatal("❌ Failed to check search index:", err)
+	}
+	if err := db.QueryRow("SELECT COUNT(*) FROM blog_posts WHERE deleted_at IS NULL").Scan(&posts); err != nil {
+		log.Fatal("❌ Failed to check search index:", err)
+	}
+	if indexed == posts {
+		return
+	}
+
+	err = withTransaction(func(tx *sql.Tx) error {
+		if _, err := tx.Exec("DELETE FROM blog_posts_fts"); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`
+		INSERT INTO blog_posts_fts (rowid, title, meta_description, focus_keyword, tags, description)
+		` + searchDocumentQuery + ` WHERE deleted_at IS NULL`)
+		return err
+	})
+	if err != nil {
+		log.Fatal("❌ Failed to build search index:", err)
+	}
+	log.Printf("🔎 Indexed %d posts for search", posts)
+}
+
+// indexPost adds or refreshes a post in the search index. Trashed posts are
+// only removed, so the index stays in step with the live posts.
+func indexPost(tx *sql.Tx, postID int64) error {
+	if !searchEnabled {
+		return nil
+	}
+	if err := unindexPost(tx, postID); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`
+	INSERT INTO blog_posts_fts (rowid, title, meta_description, focus_keyword, tags, description)
+	`+searchDocumentQuery+` WHERE id = ? AND deleted_at IS NULL`, postID)
+	return err
+}
+
+// unindexPost removes a post from the search index
+func unindexPost(tx *sql.Tx, postID int64) error {
+	if !searchEnabled {
+		return nil
+	}
+	_, err := tx.Exec("DELETE FROM blog_posts_fts WHERE rowid = ?", postID)
+	return err
+}
+
+// buildMatchQuery turns free text into an FTS5 query matching every word.
+// Each word is quoted so punctuation in user input can't break the syntax.
+func buildMatchQuery(text string) string {
+	var terms []string
+	for _, word := range strings.Fields(text) {
+		terms = append(terms, `"`+strings.ReplaceAll(word, `"`, `""`)+`"`)
+	}
+	return strings.Join(terms, " ")
+}
+
+// markHighlights escapes text highlighted by FTS5 and turns its markers into
+// <mark> tags, so the result is safe to render as HTML
+func markHighlights(text string) string {
+	return strings.NewReplacer(highlightStart, "<mark>", highlightEnd, "</mark>").
+		Replace(html.EscapeString(text))
+}
+
+// searchHandler runs a full-text search over blog posts
+// @Summary Search blog posts
+// @Description Full-text search over title, meta description, focus keyword, tags and description,
+// @Description ranked by bm25 with highlighted snippets. Accepts the same filters as /blogs.
+// @Tags search
+// @Produce json
+// @Param q query string true "Search text"
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Number of items per page"
+// @Param tags query string false "Comma-separated tags to filter by"
+// @Param topic query string false "Topic"
+// @Param service query string false "Service"
+// @Param industry query string false "Industry"
+// @Param priority query string false "Comma-separated priorities"
+// @Success 200 {object} SearchResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /search [get]
+func searchHandler(w http.ResponseWriter, r *http.Request) {
+	if !searchEnabled {
+		writeErrorResponse(w, http.StatusServiceUnavailable, "Search is not available on this server")
+		return
+	}
+
+	text := strings.TrimSpace(r.URL.Query().Get("q"))
+	match := buildMatchQuery(text)
+	if match == "" {
+		writeErrorResponse(w, http.StatusBadRequest, "q is required")
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	filter, err := parsePostFilter(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	where, filterArgs := filter.where()
+	args := append([]interface{}{match}, filterArgs...)
+
+	var totalPosts int
+	err = db.QueryRow(`
+	SELECT COUNT(*) FROM blog_posts
+	JOIN (SELECT rowid AS post_id FROM blog_posts_fts WHERE blog_posts_fts MATCH ?) AS m
+		ON m.post_id = blog_posts.id
+	WHERE `+where, args...).Scan(&totalPosts)
+	if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Could not run search")
+		return
+	}
+
+	// Title and meta data weigh more than the body text
+	rows, err := db.Query(`
+	SELECT `+blogPostColumns+`, m.score, m.title_highlight, m.snippet
+	FROM blog_posts
+	JOIN (
+		SELECT rowid AS post_id,
+			bm25(blog_posts_fts, 10.0, 5.0, 5.0, 3.0, 1.0) AS score,
+			highlight(blog_posts_fts, 0, ?, ?) AS title_highlight,
+			snippet(blog_posts_fts, 4, ?, ?, '…', 32) AS snippet
+		FROM blog_posts_fts WHERE blog_posts_fts MATCH ?
+	) AS m ON m.post_id = blog_posts.id
+	WHERE `+where+`
+	ORDER BY m.score, blog_posts.id
+	LIMIT ? OFFSET ?`, append(append([]interface{}{highlightStart, highlightEnd, highlightStart, highlightEnd}, args...), pageSize, (page-1)*pageSize)...)
+	if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Could not run search")
+		return
+	}
+	defer rows.Close()
+
+	results := []SearchResult{}
+	for rows.Next() {
+		var result SearchResult
+		post, err := scanBlogPost(rows, &result.Score, &result.TitleHighlight, &result.Snippet)
+		if err != nil {
+			continue
+		}
+		result.BlogPost = post
+		result.TitleHighlight = markHighlights(result.TitleHighlight)
+		result.Snippet = markHighlights(result.Snippet)
+		results = append(results, result)
+	}
+
+	writeJSONResponse(w, http.StatusOK, SearchResponse{
+		Query:      text,
+		Posts:      results,
+		TotalPosts: totalPosts,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: (totalPosts + pageSize - 1) / pageSize,
+	})
+}
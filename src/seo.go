@@ -0,0 +1,302 @@
+package main
+
+import (
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"mime"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SEOData is the schema.org JSON-LD of a blog post: a single @graph holding
+// the web page, the BlogPosting itself, its author and publisher and a
+// breadcrumb trail
+// @swagger:model
+type SEOData struct {
+	// The JSON-LD context
+	Context string `json:"@context"`
+
+	// The linked nodes: WebPage, BlogPosting, Person (when the post has an
+	// author), Organization and BreadcrumbList
+	Graph []interface{} `json:"@graph"`
+}
+
+// LDRef points to another node of the graph by its @id
+type LDRef struct {
+	ID string `json:"@id"`
+}
+
+// LDImage is a schema.org ImageObject
+type LDImage struct {
+	Type string `json:"@type"`
+	URL  string `json:"url"`
+}
+
+// LDWebPage is the page a blog post is shown on
+type LDWebPage struct {
+	Type       string `json:"@type"`
+	ID         string `json:"@id"`
+	URL        string `json:"url"`
+	Name       string `json:"name"`
+	Breadcrumb LDRef  `json:"breadcrumb"`
+}
+
+// LDBlogPosting describes the blog post itself
+type LDBlogPosting struct {
+	Type             string   `json:"@type"`
+	ID               string   `json:"@id"`
+	Headline         string   `json:"headline"`
+	Description      string   `json:"description,omitempty"`
+	Keywords         []string `json:"keywords,omitempty"`
+	Image            string   `json:"image,omitempty"`
+	URL              string   `json:"url"`
+	DatePublished    string   `json:"datePublished"`
+	DateModified     string   `json:"dateModified"`
+	Author           LDRef    `json:"author"`
+	Publisher        LDRef    `json:"publisher"`
+	MainEntityOfPage LDRef    `json:"mainEntityOfPage"`
+	ArticleSection   string   `json:"articleSection,omitempty"`
+}
+
+// LDOrganization is the site's publisher
+type LDOrganization struct {
+	Type string   `json:"@type"`
+	ID   string   `json:"@id"`
+	Name string   `json:"name"`
+	URL  string   `json:"url"`
+	Logo *LDImage `json:"logo,omitempty"`
+}
+
+// LDPerson is the author of a post
+type LDPerson struct {
+	Type        string   `json:"@type"`
+	ID          string   `json:"@id"`
+	Name        string   `json:"name"`
+	URL         string   `json:"url"`
+	Description string   `json:"description,omitempty"`
+	Image       *LDImage `json:"image,omitempty"`
+	SameAs      []string `json:"sameAs,omitempty"`
+}
+
+// LDBreadcrumbList is the trail from the home page down to a post
+type LDBreadcrumbList struct {
+	Type            string       `json:"@type"`
+	ID              string       `json:"@id"`
+	ItemListElement []LDListItem `json:"itemListElement"`
+}
+
+// LDListItem is one step of a breadcrumb trail
+type LDListItem struct {
+	Type     string `json:"@type"`
+	Position int    `json:"position"`
+	Name     string `json:"name"`
+	Item     string `json:"item,omitempty"`
+}
+
+// OpenGraph holds a post's Open Graph tags, keyed by their meta property
+// names so they can be rendered as-is. article:tag is repeated per tag.
+// @swagger:model
+type OpenGraph struct {
+	Type          string   `json:"og:type"`
+	Title         string   `json:"og:title"`
+	Description   string   `json:"og:description,omitempty"`
+	URL           string   `json:"og:url"`
+	SiteName      string   `json:"og:site_name"`
+	Image         string   `json:"og:image,omitempty"`
+	ImageType     string   `json:"og:image:type,omitempty"`
+	ImageWidth    int      `json:"og:image:width,omitempty"`
+	ImageHeight   int      `json:"og:image:height,omitempty"`
+	PublishedTime string   `json:"article:published_time"`
+	Author        string   `json:"article:author,omitempty"`
+	ModifiedTime  string   `json:"article:modified_time"`
+	Section       string   `json:"article:section,omitempty"`
+	Tags          []string `json:"article:tag,omitempty"`
+}
+
+// TwitterCard holds a post's Twitter Card tags, keyed by their meta names
+// @swagger:model
+type TwitterCard struct {
+	Card        string `json:"twitter:card"`
+	Title       string `json:"twitter:title"`
+	Description string `json:"twitter:description,omitempty"`
+	Image       string `json:"twitter:image,omitempty"`
+}
+
+// postImageURL returns the absolute URL of a post's image, falling back to
+// the site's default image
+func postImageURL(post BlogPost) string {
+	if post.ImageURL != "" {
+		return post.ImageURL
+	}
+	return absoluteURL(site.DefaultImage)
+}
+
+// postImageFile returns the local file behind a post's image, or "" when the
+// image is hosted elsewhere
+func postImageFile(post BlogPost) string {
+	path := post.Image
+	if path == "" {
+		path = site.DefaultImage
+	}
+	if u, err := url.Parse(path); path == "" || err != nil || u.IsAbs() {
+		return ""
+	}
+	return filepath.Clean(strings.TrimPrefix(path, "/"))
+}
+
+// imageDimensions reads the width and height of an image file from its header
+func imageDimensions(path string) (width, height int, ok bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0, false
+	}
+	defer file.Close()
+
+	config, _, err := image.DecodeConfig(file)
+	if err != nil {
+		return 0, 0, false
+	}
+	return config.Width, config.Height, true
+}
+
+// buildOpenGraph builds the Open Graph tags of a blog post
+func buildOpenGraph(post BlogPost) OpenGraph {
+	og := OpenGraph{
+		Type:          "article",
+		Title:         post.Title,
+		Description:   post.MetaDescription,
+		URL:           postURL(post.UrlKeyword),
+		SiteName:      site.Name,
+		Image:         postImageURL(post),
+		PublishedTime: post.CreatedAt,
+		ModifiedTime:  post.UpdatedAt,
+		Section:       post.Topic,
+		Tags:          post.Tags,
+	}
+	if post.Author != nil {
+		og.Author = absoluteURL(authorPath(post.Author.Slug))
+	}
+
+	if file := postImageFile(post); file != "" {
+		og.ImageType = mime.TypeByExtension(filepath.Ext(file))
+		if width, height, ok := imageDimensions(file); ok {
+			og.ImageWidth, og.ImageHeight = width, height
+		}
+	}
+	return og
+}
+
+// buildTwitterCard builds the Twitter Card tags of a blog post, using a
+// large image card when there is an image to show
+func buildTwitterCard(post BlogPost) TwitterCard {
+	card := TwitterCard{
+		Card:        "summary",
+		Title:       post.Title,
+		Description: post.MetaDescription,
+		Image:       postImageURL(post),
+	}
+	if card.Image != "" {
+		card.Card = "summary_large_image"
+	}
+	return card
+}
+
+// publisherNode describes the site as the organization publishing its posts
+func publisherNode() LDOrganization {
+	publisher := LDOrganization{
+		Type: "Organization",
+		ID:   absoluteURL("/#organization"),
+		Name: site.Name,
+		URL:  absoluteURL("/"),
+	}
+	if site.Logo != "" {
+		publisher.Logo = &LDImage{Type: "ImageObject", URL: absoluteURL(site.Logo)}
+	}
+	return publisher
+}
+
+// authorNode describes the author of a post as a schema.org Person
+func authorNode(author Author) LDPerson {
+	person := LDPerson{
+		Type:        "Person",
+		ID:          absoluteURL("/authors/"+author.Slug) + "#person",
+		Name:        author.Name,
+		URL:         absoluteURL(authorPath(author.Slug)),
+		Description: author.Bio,
+		SameAs:      author.Links,
+	}
+	if author.AvatarURL != "" {
+		person.Image = &LDImage{Type: "ImageObject", URL: author.AvatarURL}
+	}
+	return person
+}
+
+// buildSEOData builds the JSON-LD graph of a blog post. Posts without an
+// author are credited to the publisher.
+func buildSEOData(post BlogPost) SEOData {
+	pageURL := postURL(post.UrlKeyword)
+	publisher := publisherNode()
+	author := LDRef{ID: publisher.ID}
+	var person *LDPerson
+	if post.Author != nil {
+		node := authorNode(*post.Author)
+		person = &node
+		author = LDRef{ID: node.ID}
+	}
+
+	var keywords []string
+	for _, keyword := range append([]string{post.FocusKeyword}, post.Tags...) {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			keywords = append(keywords, keyword)
+		}
+	}
+
+	// Home > Topic > Post, the last step being the current page
+	crumbs := []LDListItem{{Type: "ListItem", Name: site.Name, Item: absoluteURL("/")}}
+	if post.Topic != "" {
+		crumbs = append(crumbs, LDListItem{Type: "ListItem", Name: post.Topic, Item: absoluteURL(listingPath("topic", post.Topic))})
+	}
+	crumbs = append(crumbs, LDListItem{Type: "ListItem", Name: post.Title})
+	for i := range crumbs {
+		crumbs[i].Position = i + 1
+	}
+
+	graph := []interface{}{
+		LDWebPage{
+			Type:       "WebPage",
+			ID:         pageURL,
+			URL:        pageURL,
+			Name:       post.Title,
+			Breadcrumb: LDRef{ID: pageURL + "#breadcrumb"},
+		},
+		LDBlogPosting{
+			Type:             "BlogPosting",
+			ID:               pageURL + "#article",
+			Headline:         post.Title,
+			Description:      post.MetaDescription,
+			Keywords:         keywords,
+			Image:            postImageURL(post),
+			URL:              pageURL,
+			DatePublished:    post.CreatedAt,
+			DateModified:     post.UpdatedAt,
+			Author:           author,
+			Publisher:        LDRef{ID: publisher.ID},
+			MainEntityOfPage: LDRef{ID: pageURL},
+			ArticleSection:   post.Topic,
+		},
+	}
+	if person != nil {
+		graph = append(graph, *person)
+	}
+	graph = append(graph, publisher, LDBreadcrumbList{
+		Type:            "BreadcrumbList",
+		ID:              pageURL + "#breadcrumb",
+		ItemListElement: crumbs,
+	})
+
+	return SEOData{Context: "https://schema.org", Graph: graph}
+}
@@ -0,0 +1,389 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// A session starts when a user logs in and ends when they log out, when it
+// is revoked, or when its refresh token goes unused for REFRESH_TOKEN_TTL.
+// Every refresh swaps the refresh token for a new one. Presenting a refresh
+// token that was already swapped means it was copied, so the whole session
+// is revoked.
+
+// refreshTokenPrefix tells refresh tokens apart from API keys
+const refreshTokenPrefix = "rt_"
+
+var errInvalidCredentials = errors.New("invalid email or password")
+
+// TokenResponse is returned when logging in or refreshing a session
+// @swagger:model
+type TokenResponse struct {
+	// Short-lived JWT to send as "Authorization: Bearer <token>"
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type" example:"Bearer"`
+
+	// Seconds until the access token expires
+	ExpiresIn int64 `json:"expires_in"`
+
+	// Single-use token to get a new access token from /auth/refresh
+	RefreshToken string `json:"refresh_token"`
+
+	// Seconds until the refresh token expires
+	RefreshExpiresIn int64 `json:"refresh_expires_in"`
+
+	User User `json:"user"`
+}
+
+// dummyPasswordHash is compared against when the email is unknown, so
+// logging in takes as long whether or not the account exists
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("not a real password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// authenticateUser checks an email and password, returning the user they
+// belong to or errInvalidCredentials
+func authenticateUser(email, password string) (User, error) {
+	var user User
+	var hash string
+	err := db.QueryRow("SELECT id, email, name, role, created_at, password_hash FROM users WHERE email = ?",
+		strings.ToLower(strings.TrimSpace(email))).
+		Scan(&user.ID, &user.Email, &user.Name, &user.Role, &user.CreatedAt, &hash)
+	if err == sql.ErrNoRows {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(password))
+		return User{}, errInvalidCredentials
+	} else if err != nil {
+		return User{}, err
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		return User{}, errInvalidCredentials
+	}
+	return user, nil
+}
+
+// issueTokens hands out a new access token and refresh token for a session,
+// extending it by REFRESH_TOKEN_TTL
+func issueTokens(tx *sql.Tx, user User, sessionID int64) (TokenResponse, error) {
+	refreshToken, err := randomToken(refreshTokenPrefix)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+	if _, err := tx.Exec("INSERT INTO refresh_tokens (token_hash, session_id) VALUES (?, ?)",
+		hashToken(refreshToken), sessionID); err != nil {
+		return TokenResponse{}, err
+	}
+
+	now := time.Now()
+	if _, err := tx.Exec("UPDATE sessions SET expires_at = ?, last_used_at = CURRENT_TIMESTAMP WHERE id = ?",
+		now.Add(refreshTokenTTL).UTC().Format(sqliteTimeFormat), sessionID); err != nil {
+		return TokenResponse{}, err
+	}
+
+	accessToken, err := signAccessToken(AccessClaims{
+		Issuer:    site.BaseURL,
+		Subject:   strconv.FormatInt(user.ID, 10),
+		SessionID: sessionID,
+		Role:      user.Role,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(accessTokenTTL).Unix(),
+	})
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	return TokenResponse{
+		AccessToken:      accessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(accessTokenTTL.Seconds()),
+		RefreshToken:     refreshToken,
+		RefreshExpiresIn: int64(refreshTokenTTL.Seconds()),
+		User:             user,
+	}, nil
+}
+
+// startSession opens a session for a user who just logged in
+func startSession(user User) (TokenResponse, error) {
+	var tokens TokenResponse
+	err := withTransaction(func(tx *sql.Tx) error {
+		// Expired sessions can't be refreshed any more, so their tokens can go
+		if _, err := tx.Exec(`
+		DELETE FROM refresh_tokens WHERE session_id IN (
+			SELECT id FROM sessions WHERE expires_at <= CURRENT_TIMESTAMP)`); err != nil {
+			return err
+		}
+
+		result, err := tx.Exec("INSERT INTO sessions (user_id, expires_at) VALUES (?, ?)",
+			user.ID, time.Now().Add(refreshTokenTTL).UTC().Format(sqliteTimeFormat))
+		if err != nil {
+			return err
+		}
+		sessionID, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		tokens, err = issueTokens(tx, user, sessionID)
+		return err
+	})
+	return tokens, err
+}
+
+// refreshSession swaps a refresh token for a new pair of tokens. A refresh
+// token that was already used revokes its session.
+func refreshSession(refreshToken string) (TokenResponse, error) {
+	var tokens TokenResponse
+	reused := false
+
+	err := withTransaction(func(tx *sql.Tx) error {
+		var sessionID int64
+		var used, active bool
+		var user User
+		err := tx.QueryRow(`
+		SELECT t.session_id, t.used_at IS NOT NULL,
+			s.revoked_at IS NULL AND s.expires_at > CURRENT_TIMESTAMP,
+			u.id, u.email, u.name, u.role, u.created_at
+		FROM refresh_tokens t
+		JOIN sessions s ON s.id = t.session_id
+		JOIN users u ON u.id = s.user_id
+		WHERE t.token_hash = ?`, hashToken(refreshToken)).
+			Scan(&sessionID, &used, &active, &user.ID, &user.Email, &user.Name, &user.Role, &user.CreatedAt)
+		if err == sql.ErrNoRows {
+			return errInvalidToken
+		} else if err != nil {
+			return err
+		}
+		if !active {
+			return errInvalidToken
+		}
+		if used {
+			// Commit the revocation, then report the token as invalid
+			reused = true
+			return revokeSession(tx, sessionID)
+		}
+
+		if _, err := tx.Exec("UPDATE refresh_tokens SET used_at = CURRENT_TIMESTAMP WHERE token_hash = ?",
+			hashToken(refreshToken)); err != nil {
+			return err
+		}
+		tokens, err = issueTokens(tx, user, sessionID)
+		return err
+	})
+	if err == nil && reused {
+		return TokenResponse{}, errInvalidToken
+	}
+	return tokens, err
+}
+
+// revokeSession ends a session. Its refresh tokens are deleted, as none of
+// them can be used any more.
+func revokeSession(tx *sql.Tx, sessionID int64) error {
+	if _, err := tx.Exec("UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP WHERE id = ? AND revoked_at IS NULL",
+		sessionID); err != nil {
+		return err
+	}
+	_, err := tx.Exec("DELETE FROM refresh_tokens WHERE session_id = ?", sessionID)
+	return err
+}
+
+// revokeUserSessions ends every active session of a user and returns how
+// many there were
+func revokeUserSessions(tx *sql.Tx, userID int64) (int64, error) {
+	if _, err := tx.Exec(`
+	DELETE FROM refresh_tokens WHERE session_id IN (SELECT id FROM sessions WHERE user_id = ?)`, userID); err != nil {
+		return 0, err
+	}
+	result, err := tx.Exec(`
+	UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP
+	WHERE user_id = ? AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP`, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// lookupSession verifies an access token and checks that its session is
+// still active. The role comes from the users table rather than the token,
+// so role changes apply straight away.
+func lookupSession(token string) (*Principal, error) {
+	claims, err := parseAccessToken(token)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil {
+		return nil, errInvalidToken
+	}
+
+	principal := Principal{UserID: userID, SessionID: claims.SessionID}
+	err = db.QueryRow(`
+	SELECT u.name, u.role FROM sessions s
+	JOIN users u ON u.id = s.user_id
+	WHERE s.id = ? AND s.user_id = ? AND s.revoked_at IS NULL AND s.expires_at > CURRENT_TIMESTAMP`,
+		claims.SessionID, userID).Scan(&principal.Name, &principal.Role)
+	if err == sql.ErrNoRows {
+		return nil, errInvalidToken
+	} else if err != nil {
+		return nil, err
+	}
+	return &principal, nil
+}
+
+// parseAuthForm parses the form of an /auth request, which must be a POST
+func parseAuthForm(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return false
+	}
+	if err := r.ParseMultipartForm(maxFileSize); err != nil && err != http.ErrNotMultipart {
+		writeErrorResponse(w, http.StatusBadRequest, "Failed to parse form data")
+		return false
+	}
+	return true
+}
+
+// loginHandler logs a user in
+// @Summary Log in
+// @Description Log in with an email and password. Returns a short-lived access token to send as a bearer token,
+// @Description and a refresh token to get a new one from /auth/refresh before it expires.
+// @Tags auth
+// @Accept multipart/form-data,application/x-www-form-urlencoded
+// @Produce json
+// @Param email formData string true "Email address"
+// @Param password formData string true "Password"
+// @Success 200 {object} TokenResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/login [post]
+func loginHandler(w http.ResponseWriter, r *http.Request) {
+	if !parseAuthForm(w, r) {
+		return
+	}
+	email, password := r.FormValue("email"), r.FormValue("password")
+	if strings.TrimSpace(email) == "" || password == "" {
+		writeErrorResponse(w, http.StatusBadRequest, "email and password are required")
+		return
+	}
+
+	user, err := authenticateUser(email, password)
+	if err == errInvalidCredentials {
+		authFailed(r)
+		writeErrorResponse(w, http.StatusUnauthorized, "Invalid email or password")
+		return
+	} else if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to log in")
+		return
+	}
+
+	tokens, err := startSession(user)
+	if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to log in")
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, tokens)
+}
+
+// refreshHandler swaps a refresh token for new tokens
+// @Summary Refresh a session
+// @Description Exchange a refresh token for a new access token and refresh token. Each refresh token works once;
+// @Description reusing one ends the session.
+// @Tags auth
+// @Accept multipart/form-data,application/x-www-form-urlencoded
+// @Produce json
+// @Param refresh_token formData string true "Refresh token"
+// @Success 200 {object} TokenResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/refresh [post]
+func refreshHandler(w http.ResponseWriter, r *http.Request) {
+	if !parseAuthForm(w, r) {
+		return
+	}
+	refreshToken := r.FormValue("refresh_token")
+	if refreshToken == "" {
+		writeErrorResponse(w, http.StatusBadRequest, "refresh_token is required")
+		return
+	}
+
+	tokens, err := refreshSession(refreshToken)
+	if err == errInvalidToken {
+		authFailed(r)
+		writeErrorResponse(w, http.StatusUnauthorized, "Invalid, expired or revoked refresh token")
+		return
+	} else if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to refresh session")
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, tokens)
+}
+
+// logoutHandler ends a session
+// @Summary Log out
+// @Description End the session of the access token used. Once the access token has expired, send the session's
+// @Description refresh token instead, without an Authorization header. With all=true, every session of the user is ended.
+// @Tags auth
+// @Accept multipart/form-data,application/x-www-form-urlencoded
+// @Produce json
+// @Security BearerAuth
+// @Param refresh_token formData string false "Refresh token of the session, if no access token is sent"
+// @Param all formData bool false "End every session of the user"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/logout [post]
+func logoutHandler(w http.ResponseWriter, r *http.Request) {
+	if !parseAuthForm(w, r) {
+		return
+	}
+	all, _ := strconv.ParseBool(r.FormValue("all"))
+
+	var sessionID, userID int64
+	if principal := principalFrom(r); principal != nil && principal.SessionID != 0 {
+		sessionID, userID = principal.SessionID, principal.UserID
+	} else if refreshToken := r.FormValue("refresh_token"); refreshToken != "" {
+		err := db.QueryRow(`
+		SELECT s.id, s.user_id FROM refresh_tokens t JOIN sessions s ON s.id = t.session_id
+		WHERE t.token_hash = ?`, hashToken(refreshToken)).Scan(&sessionID, &userID)
+		if err == sql.ErrNoRows {
+			authFailed(r)
+			writeErrorResponse(w, http.StatusUnauthorized, "Invalid, expired or revoked refresh token")
+			return
+		} else if err != nil {
+			fmt.Println(err)
+			writeErrorResponse(w, http.StatusInternalServerError, "Failed to log out")
+			return
+		}
+	} else {
+		writeErrorResponse(w, http.StatusBadRequest, "Send the session's access token or its refresh_token")
+		return
+	}
+
+	err := withTransaction(func(tx *sql.Tx) error {
+		if all {
+			_, err := revokeUserSessions(tx, userID)
+			return err
+		}
+		return revokeSession(tx, sessionID)
+	})
+	if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to log out")
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, map[string]string{"message": "Logged out"})
+}
@@ -0,0 +1,390 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sitemapMaxURLs is the most URLs a single sitemap may list per sitemaps.org.
+// Above it /sitemap.xml becomes an index of paginated child sitemaps.
+const sitemapMaxURLs = 50000
+
+const (
+	sitemapNamespace      = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	imageSitemapNamespace = "http://www.google.com/schemas/sitemap-image/1.1"
+)
+
+// taxonomyPriority is the sitemap priority of tag, topic, service, industry
+// and author listing pages, below that of the posts themselves
+const taxonomyPriority = "0.4"
+
+// sitemapWhere selects the posts listed in the sitemap
+const sitemapWhere = "deleted_at IS NULL AND status = 'published'"
+
+// URL represents an entry in the sitemap
+// @swagger:model
+type URL struct {
+	// The absolute URL of the blog post
+	Loc string `xml:"loc" json:"loc"`
+
+	// When the blog post was last modified (W3C datetime)
+	LastMod string `xml:"lastmod,omitempty" json:"lastmod,omitempty"`
+
+	// The change frequency of the URL
+	Change string `xml:"changefreq" json:"changefreq"`
+
+	// The priority of the URL in the sitemap, from 0.0 to 1.0
+	Priority string `xml:"priority" json:"priority"`
+
+	// Images shown on the page
+	Images []SitemapImage `xml:"image:image" json:"images,omitempty"`
+}
+
+// SitemapImage is an image entry from the Google image sitemap extension
+// @swagger:model
+type SitemapImage struct {
+	// The absolute URL of the image
+	Loc string `xml:"image:loc" json:"loc"`
+}
+
+// Sitemap represents the structure of the sitemap.xml
+// @swagger:model
+type Sitemap struct {
+	XMLName xml.Name `xml:"urlset" json:"-"`
+	XMLNS   string   `xml:"xmlns,attr" json:"-"`
+	ImageNS string   `xml:"xmlns:image,attr" json:"-"`
+
+	// List of URLs in the sitemap
+	Urls []URL `xml:"url" json:"urls"`
+}
+
+// SitemapRef points to a child sitemap from a sitemap index
+// @swagger:model
+type SitemapRef struct {
+	// The absolute URL of the child sitemap
+	Loc string `xml:"loc" json:"loc"`
+
+	// When a post in the child sitemap was last modified (W3C datetime)
+	LastMod string `xml:"lastmod,omitempty" json:"lastmod,omitempty"`
+}
+
+// SitemapIndex lists child sitemaps once there are too many URLs for one
+// @swagger:model
+type SitemapIndex struct {
+	XMLName xml.Name `xml:"sitemapindex" json:"-"`
+	XMLNS   string   `xml:"xmlns,attr" json:"-"`
+
+	// List of child sitemaps
+	Sitemaps []SitemapRef `xml:"sitemap" json:"sitemaps"`
+}
+
+// sitemapPriority maps a post priority onto the 0.0–1.0 scale sitemaps use,
+// spreading PriorityWeight evenly between 0.5 and 1.0
+func sitemapPriority(priority string) string {
+	maxWeight := 1
+	for _, weight := range PriorityWeight {
+		maxWeight = max(maxWeight, weight)
+	}
+
+	weight, ok := PriorityWeight[priority]
+	if !ok || maxWeight == 1 {
+		return "0.5"
+	}
+	value := 0.5 + 0.5*float64(weight-1)/float64(maxWeight-1)
+	return strconv.FormatFloat(value, 'f', 1, 64)
+}
+
+// changeFrequency guesses how often a post changes from how recently it
+// was last updated: recently edited posts are likely to be edited again
+func changeFrequency(updated, now time.Time) string {
+	switch age := now.Sub(updated); {
+	case age < 24*time.Hour:
+		return "daily"
+	case age < 7*24*time.Hour:
+		return "weekly"
+	case age < 180*24*time.Hour:
+		return "monthly"
+	default:
+		return "yearly"
+	}
+}
+
+// sitemapURLs returns one page of sitemap entries, ordered by post ID so
+// pages stay stable as posts are added
+func sitemapURLs(page int) ([]URL, error) {
+	rows, err := db.Query(`
+	SELECT url_keyword, priority, image, updated_at FROM blog_posts
+	WHERE `+sitemapWhere+`
+	ORDER BY id LIMIT ? OFFSET ?`, sitemapMaxURLs, (page-1)*sitemapMaxURLs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	urls := []URL{}
+	for rows.Next() {
+		var urlKeyword, priority, image, updatedAt string
+		if err := rows.Scan(&urlKeyword, &priority, &image, &updatedAt); err != nil {
+			continue
+		}
+
+		updated := parseDBTime(updatedAt)
+		entry := URL{
+			Loc:      postURL(urlKeyword),
+			LastMod:  updated.Format(time.RFC3339),
+			Change:   changeFrequency(updated, now),
+			Priority: sitemapPriority(priority),
+		}
+		if image != "" {
+			entry.Images = []SitemapImage{{Loc: absoluteURL(filepath.ToSlash(image))}}
+		}
+		urls = append(urls, entry)
+	}
+	return urls, rows.Err()
+}
+
+// taxonomyURLs lists the /posts listing page of every tag, topic, service,
+// industry and author of a published post, modified whenever one of its posts
+// was. The JSON API listings are not pages, so nothing is listed without
+// HTML pages.
+func taxonomyURLs() ([]URL, error) {
+	if !site.HTMLPages {
+		return nil, nil
+	}
+
+	rows, err := db.Query(`
+	SELECT 'tags', t.name, MAX(updated_at) FROM tags t
+	JOIN post_tags pt ON pt.tag_id = t.id
+	JOIN blog_posts ON blog_posts.id = pt.post_id
+	WHERE ` + sitemapWhere + ` GROUP BY t.id
+	UNION ALL
+	SELECT 'topic', topic, MAX(updated_at) FROM blog_posts
+	WHERE ` + sitemapWhere + ` AND topic != '' GROUP BY topic
+	UNION ALL
+	SELECT 'service', service, MAX(updated_at) FROM blog_posts
+	WHERE ` + sitemapWhere + ` AND service != '' GROUP BY service
+	UNION ALL
+	SELECT 'industry', industry, MAX(updated_at) FROM blog_posts
+	WHERE ` + sitemapWhere + ` AND industry != '' GROUP BY industry
+	UNION ALL
+	SELECT 'author', a.slug, MAX(blog_posts.updated_at) FROM authors a
+	JOIN blog_posts ON blog_posts.author_id = a.id
+	WHERE ` + sitemapWhere + ` GROUP BY a.id
+	ORDER BY 1, 2`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	urls := []URL{}
+	for rows.Next() {
+		var param, value, updatedAt string
+		if err := rows.Scan(&param, &value, &updatedAt); err != nil {
+			continue
+		}
+
+		// MAX() loses the column type, so the time comes back as stored
+		updated, _ := time.Parse(sqliteTimeFormat, updatedAt)
+		urls = append(urls, URL{
+			Loc:      absoluteURL(listingPath(param, value)),
+			LastMod:  updated.Format(time.RFC3339),
+			Change:   changeFrequency(updated, now),
+			Priority: taxonomyPriority,
+		})
+	}
+	return urls, rows.Err()
+}
+
+// taxonomyPage returns one page of taxonomy sitemap entries, or nil past the
+// last page
+func taxonomyPage(urls []URL, page int) []URL {
+	start := (page - 1) * sitemapMaxURLs
+	if page < 1 || start >= len(urls) {
+		return nil
+	}
+	return urls[start:min(start+sitemapMaxURLs, len(urls))]
+}
+
+// latestLastMod returns the most recent lastmod of a list of sitemap entries
+func latestLastMod(urls []URL) string {
+	latest := ""
+	for _, entry := range urls {
+		// RFC 3339 times in UTC sort lexically
+		if entry.LastMod > latest {
+			latest = entry.LastMod
+		}
+	}
+	return latest
+}
+
+// newSitemap wraps sitemap entries in a urlset
+func newSitemap(urls []URL) Sitemap {
+	return Sitemap{XMLNS: sitemapNamespace, ImageNS: imageSitemapNamespace, Urls: urls}
+}
+
+// sitemapIndex lists a child sitemap for every page of posts, each with the
+// latest modification time of its posts, followed by the taxonomy sitemaps
+func sitemapIndex(taxonomies []URL) (SitemapIndex, error) {
+	index := SitemapIndex{XMLNS: sitemapNamespace}
+
+	rows, err := db.Query(`
+	SELECT (n - 1) / ? + 1 AS page, strftime('%Y-%m-%dT%H:%M:%SZ', MAX(updated_at))
+	FROM (
+		SELECT updated_at, ROW_NUMBER() OVER (ORDER BY id) AS n
+		FROM blog_posts WHERE `+sitemapWhere+`
+	)
+	GROUP BY page ORDER BY page`, sitemapMaxURLs)
+	if err != nil {
+		return index, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var page int
+		var lastMod string
+		if err := rows.Scan(&page, &lastMod); err != nil {
+			return index, err
+		}
+		index.Sitemaps = append(index.Sitemaps, SitemapRef{
+			Loc:     absoluteURL(fmt.Sprintf("/sitemaps/%d.xml", page)),
+			LastMod: lastMod,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return index, err
+	}
+
+	for page := 1; ; page++ {
+		urls := taxonomyPage(taxonomies, page)
+		if urls == nil {
+			break
+		}
+		index.Sitemaps = append(index.Sitemaps, SitemapRef{
+			Loc:     absoluteURL(fmt.Sprintf("/sitemaps/taxonomies-%d.xml", page)),
+			LastMod: latestLastMod(urls),
+		})
+	}
+	return index, nil
+}
+
+// writeXML writes an XML document with its declaration
+func writeXML(w http.ResponseWriter, document interface{}) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Write([]byte(xml.Header))
+	if err := xml.NewEncoder(w).Encode(document); err != nil {
+		log.Printf("Failed to write sitemap: %v", err)
+	}
+}
+
+// sitemapHandler generates a sitemap
+// @Summary Generate sitemap.xml
+// @Description Generate an XML sitemap of published blog posts with their images and, when HTML pages are
+// @Description enabled, of the tag, topic, service, industry and author listing pages. Above 50,000 URLs this is
+// @Description a sitemap index pointing at paginated child sitemaps under /sitemaps/{page}.xml and
+// @Description /sitemaps/taxonomies-{page}.xml.
+// @Tags sitemap
+// @Produce xml
+// @Success 200 {object} Sitemap
+// @Failure 500 {object} map[string]string
+// @Router /sitemap.xml [get]
+func sitemapHandler(w http.ResponseWriter, r *http.Request) {
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM blog_posts WHERE " + sitemapWhere).Scan(&count); err != nil {
+		http.Error(w, "Could not generate sitemap", http.StatusInternalServerError)
+		return
+	}
+
+	taxonomies, err := taxonomyURLs()
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Could not generate sitemap", http.StatusInternalServerError)
+		return
+	}
+
+	if count+len(taxonomies) > sitemapMaxURLs {
+		index, err := sitemapIndex(taxonomies)
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Could not generate sitemap", http.StatusInternalServerError)
+			return
+		}
+		writeXML(w, index)
+		return
+	}
+
+	urls, err := sitemapURLs(1)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Could not generate sitemap", http.StatusInternalServerError)
+		return
+	}
+	writeXML(w, newSitemap(append(urls, taxonomies...)))
+}
+
+// sitemapPageHandler serves one page of a sitemap index
+// @Summary Get a child sitemap
+// @Description Get one page of up to 50,000 published blog posts, as listed in the sitemap index.
+// @Description /sitemaps/taxonomies-{page}.xml lists up to 50,000 tag, topic, service, industry and author
+// @Description listing pages, and is only available with HTML pages enabled.
+// @Tags sitemap
+// @Produce xml
+// @Param page path int true "Page number, starting at 1"
+// @Success 200 {object} Sitemap
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /sitemaps/{page}.xml [get]
+func sitemapPageHandler(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/sitemaps/")
+	if !strings.HasSuffix(name, ".xml") {
+		writeErrorResponse(w, http.StatusNotFound, "Not found")
+		return
+	}
+	name = strings.TrimSuffix(name, ".xml")
+
+	if number, ok := strings.CutPrefix(name, "taxonomies-"); ok {
+		page, err := strconv.Atoi(number)
+		if err != nil || page < 1 {
+			writeErrorResponse(w, http.StatusNotFound, "Not found")
+			return
+		}
+		taxonomies, err := taxonomyURLs()
+		if err != nil {
+			fmt.Println(err)
+			http.Error(w, "Could not generate sitemap", http.StatusInternalServerError)
+			return
+		}
+		urls := taxonomyPage(taxonomies, page)
+		if urls == nil {
+			writeErrorResponse(w, http.StatusNotFound, "Not found")
+			return
+		}
+		writeXML(w, newSitemap(urls))
+		return
+	}
+
+	page, err := strconv.Atoi(name)
+	if err != nil || page < 1 {
+		writeErrorResponse(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	urls, err := sitemapURLs(page)
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Could not generate sitemap", http.StatusInternalServerError)
+		return
+	}
+	if len(urls) == 0 && page > 1 {
+		writeErrorResponse(w, http.StatusNotFound, "Not found")
+		return
+	}
+	writeXML(w, newSitemap(urls))
+}
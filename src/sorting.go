@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// defaultSort is used when a listing doesn't ask for an order: newest first
+const defaultSort = "-created_at"
+
+// sortKey is one ORDER BY term of a listing
+type sortKey struct {
+	// The sort parameter name, e.g. created_at
+	Name string
+
+	// The SQL expression sorted on
+	Expr string
+
+	// Whether the expression is sorted descending in SQL
+	Desc bool
+}
+
+// sortableFields maps each accepted sort key to its SQL expression. Fields
+// marked descByDefault read naturally from largest to smallest.
+var sortableFields = map[string]struct {
+	expr          string
+	descByDefault bool
+}{
+	"created_at": {expr: "created_at"},
+	"updated_at": {expr: "updated_at"},
+	"title":      {expr: "title COLLATE NOCASE"},
+	// priority sorts most important first, as sort=priority always has
+	"priority": {expr: priorityWeightExpr(), descByDefault: true},
+}
+
+// priorityWeightExpr builds a CASE expression mapping each priority to its
+// PriorityWeight, so unknown priorities sort after every known one
+func priorityWeightExpr() string {
+	priorities := make([]string, 0, len(PriorityWeight))
+	for priority := range PriorityWeight {
+		priorities = append(priorities, priority)
+	}
+	sort.Strings(priorities)
+
+	var b strings.Builder
+	b.WriteString("CASE priority")
+	for _, priority := range priorities {
+		fmt.Fprintf(&b, " WHEN '%s' THEN %d", priority, PriorityWeight[priority])
+	}
+	b.WriteString(" ELSE 0 END")
+	return b.String()
+}
+
+// parseSort parses a sort parameter such as "created_at,-updated_at,title".
+// A leading - reverses a key. id is always appended as a final tiebreak, in
+// the direction of the first key, so the order is stable across pages.
+func parseSort(raw string) ([]sortKey, error) {
+	if strings.TrimSpace(raw) == "" {
+		raw = defaultSort
+	}
+
+	var keys []sortKey
+	seen := map[string]bool{}
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		reverse := strings.HasPrefix(part, "-")
+		name := strings.TrimPrefix(part, "-")
+
+		field, ok := sortableFields[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown sort key %q: must be one of created_at, updated_at, title, priority", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate sort key %q", name)
+		}
+		seen[name] = true
+
+		keys = append(keys, sortKey{
+			Name: name,
+			Expr: field.expr,
+			Desc: field.descByDefault != reverse,
+		})
+	}
+
+	return append(keys, sortKey{Name: "id", Expr: "id", Desc: keys[0].Desc}), nil
+}
+
+// orderByClause renders sort keys as the body of an ORDER BY clause
+func orderByClause(keys []sortKey) string {
+	terms := make([]string, len(keys))
+	for i, key := range keys {
+		direction := "ASC"
+		if key.Desc {
+			direction = "DESC"
+		}
+		terms[i] = key.Expr + " " + direction
+	}
+	return strings.Join(terms, ", ")
+}
+
+// sortString renders sort keys back into their canonical sort parameter,
+// leaving out the id tiebreak
+func sortString(keys []sortKey) string {
+	var parts []string
+	for _, key := range keys {
+		field, ok := sortableFields[key.Name]
+		if !ok {
+			continue
+		}
+		if key.Desc != field.descByDefault {
+			parts = append(parts, "-"+key.Name)
+		} else {
+			parts = append(parts, key.Name)
+		}
+	}
+	return strings.Join(parts, ",")
+}
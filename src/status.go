@@ -0,0 +1,122 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// Post workflow statuses
+const (
+	StatusDraft     = "draft"
+	StatusInReview  = "in_review"
+	StatusPublished = "published"
+	StatusArchived  = "archived"
+)
+
+// statusTransitions maps each status to the statuses a post may move to next
+var statusTransitions = map[string][]string{
+	StatusDraft:     {StatusInReview, StatusPublished},
+	StatusInReview:  {StatusDraft, StatusPublished},
+	StatusPublished: {StatusDraft, StatusArchived},
+	StatusArchived:  {StatusDraft, StatusPublished},
+}
+
+// canTransition reports whether a post may move from one status to another
+func canTransition(from, to string) bool {
+	for _, next := range statusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// isEditorialStatus reports whether only editors may move posts into or
+// out of a status. Authors can only shuffle their work between draft and review.
+func isEditorialStatus(status string) bool {
+	return status == StatusPublished || status == StatusArchived
+}
+
+// updateStatusHandler moves a blog post through the editorial workflow
+// @Summary Change the status of a blog post
+// @Description Move a blog post between draft, in_review, published and archived.
+// @Description Allowed moves: draft → in_review/published, in_review → draft/published, published → draft/archived, archived → draft/published.
+// @Description Authors may only move posts between draft and in_review; anything else requires the editor role.
+// @Tags workflow
+// @Accept multipart/form-data,application/x-www-form-urlencoded
+// @Produce json
+// @Param urlKeyword path string true "URL Keyword of the blog post"
+// @Param status formData string true "Target status" Enums(draft, in_review, published, archived)
+// @Security BearerAuth
+// @Success 200 {object} BlogPost
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /blog/{urlKeyword}/status [post]
+func updateStatusHandler(w http.ResponseWriter, r *http.Request) {
+	urlKeyword, _ := blogPathParts(r)
+
+	target := strings.TrimSpace(r.FormValue("status"))
+	if _, ok := statusTransitions[target]; !ok {
+		writeErrorResponse(w, http.StatusBadRequest, "invalid status value: must be draft, in_review, published, or archived")
+		return
+	}
+
+	var updated BlogPost
+	var conflict, forbidden error
+	err := withTransaction(func(tx *sql.Tx) error {
+		post, err := getBlogPost(tx, urlKeyword)
+		if err != nil {
+			return err
+		}
+		if !canTransition(post.Status, target) {
+			conflict = fmt.Errorf("cannot move post from %s to %s", post.Status, target)
+			return conflict
+		}
+		if (isEditorialStatus(post.Status) || isEditorialStatus(target)) && !hasRole(r, RoleEditor) {
+			forbidden = fmt.Errorf("moving a post from %s to %s requires the editor role", post.Status, target)
+			return forbidden
+		}
+
+		// A post published by hand no longer needs its schedule
+		_, err = tx.Exec(`
+		UPDATE blog_posts SET
+			status = ?,
+			publish_at = CASE WHEN ? = 'published' THEN NULL ELSE publish_at END,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?`, target, target, post.ID)
+		if err != nil {
+			return err
+		}
+		if err := recordRevision(tx, post.ID); err != nil {
+			return err
+		}
+		updated, err = getBlogPostByID(tx, post.ID)
+		return err
+	})
+
+	if err == sql.ErrNoRows {
+		writeErrorResponse(w, http.StatusNotFound, "Blog post not found")
+		return
+	} else if conflict != nil {
+		writeErrorResponse(w, http.StatusConflict, err.Error())
+		return
+	} else if forbidden != nil {
+		writeErrorResponse(w, http.StatusForbidden, err.Error())
+		return
+	} else if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to update status")
+		return
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, updated); err != nil {
+		log.Printf("Failed to write response: %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import "testing"
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{StatusDraft, StatusInReview, true},
+		{StatusDraft, StatusPublished, true},
+		{StatusDraft, StatusArchived, false},
+		{StatusDraft, StatusDraft, false},
+		{StatusInReview, StatusDraft, true},
+		{StatusInReview, StatusPublished, true},
+		{StatusInReview, StatusArchived, false},
+		{StatusPublished, StatusDraft, true},
+		{StatusPublished, StatusArchived, true},
+		{StatusPublished, StatusInReview, false},
+		{StatusPublished, StatusPublished, false},
+		{StatusArchived, StatusDraft, true},
+		{StatusArchived, StatusPublished, true},
+		{StatusArchived, StatusInReview, false},
+		{"deleted", StatusDraft, false},
+		{StatusDraft, "deleted", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		if got := canTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("canTransition(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestIsEditorialStatus(t *testing.T) {
+	tests := map[string]bool{
+		StatusDraft:     false,
+		StatusInReview:  false,
+		StatusPublished: true,
+		StatusArchived:  true,
+	}
+
+	for status, want := range tests {
+		if got := isEditorialStatus(status); got != want {
+			t.Errorf("isEditorialStatus(%q) = %v, want %v", status, got, want)
+		}
+	}
+}
@@ -0,0 +1,384 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// TagCount is a tag together with the number of posts using it
+// @swagger:model
+type TagCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// postTagsJSON selects a post's tags, in the order they were given, as a
+// JSON array so it can be scanned like the old tags column
+const postTagsJSON = `(SELECT json_group_array(name) FROM (
+		SELECT t.name FROM post_tags pt JOIN tags t ON t.id = pt.tag_id
+		WHERE pt.post_id = blog_posts.id ORDER BY pt.position
+	)) AS tags`
+
+// setPostTags replaces the tags of a post, creating tags that don't exist yet
+func setPostTags(tx *sql.Tx, postID int64, tags []string) error {
+	if _, err := tx.Exec("DELETE FROM post_tags WHERE post_id = ?", postID); err != nil {
+		return err
+	}
+
+	for position, name := range tags {
+		if _, err := tx.Exec("INSERT OR IGNORE INTO tags (name) VALUES (?)", name); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`
+		INSERT OR IGNORE INTO post_tags (post_id, tag_id, position)
+		SELECT ?, id, ? FROM tags WHERE name = ?`, postID, position, name)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateLegacyTags moves tags stored as JSON in blog_posts.tags into the
+// tags and post_tags tables, then drops the old column. If any post's tags
+// can't be read nothing is migrated, so the column and its data are kept
+// until they are fixed.
+func migrateLegacyTags() error {
+	legacy, err := hasColumn("blog_posts", "tags")
+	if err != nil || !legacy {
+		return err
+	}
+
+	rows, err := db.Query("SELECT id, tags FROM blog_posts WHERE tags IS NOT NULL AND tags != ''")
+	if err != nil {
+		return err
+	}
+	postTags := map[int64][]string{}
+	var unreadable []int64
+	for rows.Next() {
+		var id int64
+		var tagsJSON string
+		if err := rows.Scan(&id, &tagsJSON); err != nil {
+			rows.Close()
+			return err
+		}
+		var tags []string
+		if err := json.Unmarshal([]byte(tagsJSON), &tags); err != nil {
+			log.Printf("❌ Unreadable tags of post %d: %v", id, err)
+			unreadable = append(unreadable, id)
+			continue
+		}
+		postTags[id] = tags
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(unreadable) > 0 {
+		return fmt.Errorf("the tags of posts %v are not a JSON array of strings; fix them in blog_posts.tags and restart", unreadable)
+	}
+
+	return withTransaction(func(tx *sql.Tx) error {
+		for id, tags := range postTags {
+			if err := setPostTags(tx, id, tags); err != nil {
+				return err
+			}
+		}
+		_, err := tx.Exec("ALTER TABLE blog_posts DROP COLUMN tags")
+		return err
+	})
+}
+
+// tagsRouter dispatches /tags/... admin requests
+func tagsRouter(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/tags/")
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	switch {
+	case path == "merge":
+		requireRole(RoleEditor, mergeTagsHandler)(w, r)
+	case strings.HasSuffix(path, "/rename"):
+		requireRole(RoleEditor, renameTagHandler)(w, r)
+	default:
+		writeErrorResponse(w, http.StatusNotFound, "Not found")
+	}
+}
+
+// listTagsHandler lists every tag with its usage count
+// @Summary List tags
+// @Description Get every tag with the number of posts using it, most used first.
+// @Description Anonymous callers only see counts of published posts and tags in use.
+// @Tags tags
+// @Produce json
+// @Success 200 {array} TagCount
+// @Failure 500 {object} map[string]string
+// @Router /tags [get]
+func listTagsHandler(w http.ResponseWriter, r *http.Request) {
+	visible := "p.deleted_at IS NULL AND p.status = 'published'"
+	having := "HAVING count > 0"
+	if isAuthenticated(r) {
+		visible = "p.deleted_at IS NULL"
+		having = ""
+	}
+
+	rows, err := db.Query(`
+	SELECT t.name, COUNT(p.id) AS count
+	FROM tags t
+	LEFT JOIN post_tags pt ON pt.tag_id = t.id
+	LEFT JOIN blog_posts p ON p.id = pt.post_id AND ` + visible + `
+	GROUP BY t.id ` + having + `
+	ORDER BY count DESC, t.name COLLATE NOCASE`)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "Could not fetch tags")
+		return
+	}
+	defer rows.Close()
+
+	tags := []TagCount{}
+	for rows.Next() {
+		var tag TagCount
+		if err := rows.Scan(&tag.Name, &tag.Count); err != nil {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+
+	writeJSONResponse(w, http.StatusOK, tags)
+}
+
+var errTagNotFound = errors.New("tag not found")
+
+// taggedPostIDs returns the IDs of the live posts carrying a tag. Trashed
+// posts keep their tags but are left out of the search index and untouched.
+func taggedPostIDs(tx *sql.Tx, tagID int64) ([]int64, error) {
+	rows, err := tx.Query(`
+	SELECT pt.post_id FROM post_tags pt
+	JOIN blog_posts p ON p.id = pt.post_id
+	WHERE pt.tag_id = ? AND p.deleted_at IS NULL`, tagID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// touchPosts bumps updated_at, reindexes and records a revision for posts
+// whose tags were changed by a tag-wide operation
+func touchPosts(tx *sql.Tx, ids []int64) error {
+	for _, id := range ids {
+		if _, err := tx.Exec("UPDATE blog_posts SET updated_at = CURRENT_TIMESTAMP WHERE id = ?", id); err != nil {
+			return err
+		}
+		if err := indexPost(tx, id); err != nil {
+			return err
+		}
+		if err := recordRevision(tx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renameTagHandler renames a tag on every post that uses it
+// @Summary Rename a tag
+// @Description Rename a tag across all posts. Fails if another tag already has the new name; merge them instead.
+// @Tags tags
+// @Accept multipart/form-data,application/x-www-form-urlencoded
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Current tag name"
+// @Param new_name formData string true "New tag name"
+// @Success 200 {object} TagCount
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tags/{name}/rename [post]
+func renameTagHandler(w http.ResponseWriter, r *http.Request) {
+	oldName, err := url.PathUnescape(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/tags/"), "/rename"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "invalid tag name")
+		return
+	}
+	newName := strings.TrimSpace(r.FormValue("new_name"))
+	if newName == "" || strings.Contains(newName, ",") || len(newName) > 50 {
+		writeErrorResponse(w, http.StatusBadRequest, "new_name is required, cannot contain commas and cannot exceed 50 characters")
+		return
+	}
+
+	var renamed TagCount
+	var conflict error
+	err = withTransaction(func(tx *sql.Tx) error {
+		var id int64
+		if err := tx.QueryRow("SELECT id FROM tags WHERE name = ?", oldName).Scan(&id); err == sql.ErrNoRows {
+			return errTagNotFound
+		} else if err != nil {
+			return err
+		}
+
+		var otherID int64
+		err := tx.QueryRow("SELECT id FROM tags WHERE name = ? AND id != ?", newName, id).Scan(&otherID)
+		if err == nil {
+			conflict = fmt.Errorf("tag %q already exists, merge the tags instead", newName)
+			return conflict
+		} else if err != sql.ErrNoRows {
+			return err
+		}
+
+		if _, err := tx.Exec("UPDATE tags SET name = ? WHERE id = ?", newName, id); err != nil {
+			return err
+		}
+		ids, err := taggedPostIDs(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := touchPosts(tx, ids); err != nil {
+			return err
+		}
+
+		renamed.Name = newName
+		return tx.QueryRow("SELECT COUNT(*) FROM post_tags WHERE tag_id = ?", id).Scan(&renamed.Count)
+	})
+
+	if err == errTagNotFound {
+		writeErrorResponse(w, http.StatusNotFound, "Tag not found")
+		return
+	} else if conflict != nil {
+		writeErrorResponse(w, http.StatusConflict, conflict.Error())
+		return
+	} else if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to rename tag")
+		return
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, renamed); err != nil {
+		log.Printf("Failed to write response: %v", err)
+	}
+}
+
+// mergeTagsHandler folds one or more tags into a single target tag
+// @Summary Merge tags
+// @Description Replace the source tags with the target tag on every post, then delete the source tags.
+// @Description The target tag is created if it doesn't exist.
+// @Tags tags
+// @Accept multipart/form-data,application/x-www-form-urlencoded
+// @Produce json
+// @Security BearerAuth
+// @Param from formData string true "Comma-separated tags to merge away"
+// @Param into formData string true "Tag to merge into"
+// @Success 200 {object} TagCount
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tags/merge [post]
+func mergeTagsHandler(w http.ResponseWriter, r *http.Request) {
+	// Accept both multipart and URL-encoded bodies
+	if err := r.ParseMultipartForm(maxFileSize); err != nil && err != http.ErrNotMultipart {
+		writeErrorResponse(w, http.StatusBadRequest, "Failed to parse form data")
+		return
+	}
+
+	sources := splitQueryList(r.Form["from"])
+	into := strings.TrimSpace(r.FormValue("into"))
+	if len(sources) == 0 || into == "" {
+		writeErrorResponse(w, http.StatusBadRequest, "from and into are required")
+		return
+	}
+	if strings.Contains(into, ",") || len(into) > 50 {
+		writeErrorResponse(w, http.StatusBadRequest, "into cannot contain commas or exceed 50 characters")
+		return
+	}
+
+	var merged TagCount
+	err := withTransaction(func(tx *sql.Tx) error {
+		if _, err := tx.Exec("INSERT OR IGNORE INTO tags (name) VALUES (?)", into); err != nil {
+			return err
+		}
+		var targetID int64
+		if err := tx.QueryRow("SELECT id, name FROM tags WHERE name = ?", into).Scan(&targetID, &merged.Name); err != nil {
+			return err
+		}
+
+		affected := map[int64]bool{}
+		for _, name := range sources {
+			var sourceID int64
+			if err := tx.QueryRow("SELECT id FROM tags WHERE name = ?", name).Scan(&sourceID); err == sql.ErrNoRows {
+				return errTagNotFound
+			} else if err != nil {
+				return err
+			}
+			if sourceID == targetID {
+				continue
+			}
+
+			ids, err := taggedPostIDs(tx, sourceID)
+			if err != nil {
+				return err
+			}
+			for _, id := range ids {
+				affected[id] = true
+			}
+
+			// Posts carrying both tags keep their existing target tag
+			_, err = tx.Exec(`
+			INSERT OR IGNORE INTO post_tags (post_id, tag_id, position)
+			SELECT post_id, ?, position FROM post_tags WHERE tag_id = ?`, targetID, sourceID)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Exec("DELETE FROM post_tags WHERE tag_id = ?", sourceID); err != nil {
+				return err
+			}
+			if _, err := tx.Exec("DELETE FROM tags WHERE id = ?", sourceID); err != nil {
+				return err
+			}
+		}
+
+		// Touch posts once all sources are folded in so each gets one revision
+		ids := make([]int64, 0, len(affected))
+		for id := range affected {
+			ids = append(ids, id)
+		}
+		if err := touchPosts(tx, ids); err != nil {
+			return err
+		}
+
+		return tx.QueryRow("SELECT COUNT(*) FROM post_tags WHERE tag_id = ?", targetID).Scan(&merged.Count)
+	})
+
+	if err == errTagNotFound {
+		writeErrorResponse(w, http.StatusNotFound, "Tag not found")
+		return
+	} else if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to merge tags")
+		return
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, merged); err != nil {
+		log.Printf("Failed to write response: %v", err)
+	}
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// getTrashedBlogPost fetches a blog post by its URL keyword from the trash
+func getTrashedBlogPost(q queryer, urlKeyword string) (BlogPost, error) {
+	return scanBlogPost(q.QueryRow(
+		"SELECT "+blogPostColumns+" FROM blog_posts WHERE url_keyword = ? AND deleted_at IS NOT NULL", urlKeyword))
+}
+
+// deleteBlogHandler moves a blog post to the trash
+// @Summary Delete a blog post
+// @Description Soft-delete a blog post. It is hidden from listings and the sitemap until restored or purged.
+// @Tags trash
+// @Produce json
+// @Security BearerAuth
+// @Param urlKeyword path string true "URL Keyword of the blog post"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /blog/{urlKeyword} [delete]
+func deleteBlogHandler(w http.ResponseWriter, r *http.Request) {
+	urlKeyword, _ := blogPathParts(r)
+
+	err := withTransaction(func(tx *sql.Tx) error {
+		post, err := getBlogPost(tx, urlKeyword)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec("UPDATE blog_posts SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?", post.ID); err != nil {
+			return err
+		}
+		return unindexPost(tx, post.ID)
+	})
+
+	if err == sql.ErrNoRows {
+		writeErrorResponse(w, http.StatusNotFound, "Blog post not found")
+		return
+	} else if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to delete blog post")
+		return
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, map[string]string{
+		"message": "Blog post moved to trash",
+	}); err != nil {
+		log.Printf("Failed to write response: %v", err)
+	}
+}
+
+// trashRouter dispatches /trash/{urlKeyword} requests
+func trashRouter(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/trash/")
+
+	switch {
+	case strings.HasSuffix(path, "/restore"):
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		requireRole(RoleEditor, restoreBlogHandler)(w, r)
+	case !strings.Contains(path, "/"):
+		if r.Method != http.MethodDelete {
+			w.Header().Set("Allow", "DELETE")
+			writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+			return
+		}
+		requireRole(RoleAdmin, purgeBlogHandler)(w, r)
+	default:
+		writeErrorResponse(w, http.StatusNotFound, "Not found")
+	}
+}
+
+// listTrashHandler lists the blog posts in the trash
+// @Summary List trashed blog posts
+// @Description Get a paginated list of soft-deleted blog posts, most recently deleted first
+// @Tags trash
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Number of items per page"
+// @Success 200 {object} PaginatedResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /trash [get]
+func listTrashHandler(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	var totalPosts int
+	err := db.QueryRow("SELECT COUNT(*) FROM blog_posts WHERE deleted_at IS NOT NULL").Scan(&totalPosts)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "Could not count trashed posts")
+		return
+	}
+
+	rows, err := db.Query(`SELECT `+blogPostColumns+` FROM blog_posts
+		WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC, id DESC LIMIT ? OFFSET ?`,
+		pageSize, (page-1)*pageSize)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, "Could not fetch trashed posts")
+		return
+	}
+	defer rows.Close()
+
+	var posts []BlogPost
+	for rows.Next() {
+		post, err := scanBlogPost(rows)
+		if err != nil {
+			continue
+		}
+		posts = append(posts, post)
+	}
+
+	writeJSONResponse(w, http.StatusOK, PaginatedResponse{
+		Posts:      posts,
+		TotalPosts: totalPosts,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: (totalPosts + pageSize - 1) / pageSize,
+	})
+}
+
+// restoreBlogHandler moves a blog post out of the trash
+// @Summary Restore a trashed blog post
+// @Description Restore a soft-deleted blog post so it is listed again
+// @Tags trash
+// @Produce json
+// @Security BearerAuth
+// @Param urlKeyword path string true "URL Keyword of the blog post"
+// @Success 200 {object} BlogPost
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /trash/{urlKeyword}/restore [post]
+func restoreBlogHandler(w http.ResponseWriter, r *http.Request) {
+	urlKeyword := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/trash/"), "/restore")
+
+	var restored BlogPost
+	err := withTransaction(func(tx *sql.Tx) error {
+		post, err := getTrashedBlogPost(tx, urlKeyword)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec("UPDATE blog_posts SET deleted_at = NULL WHERE id = ?", post.ID); err != nil {
+			return err
+		}
+		if err := indexPost(tx, post.ID); err != nil {
+			return err
+		}
+		restored, err = getBlogPostByID(tx, post.ID)
+		return err
+	})
+
+	if err == sql.ErrNoRows {
+		writeErrorResponse(w, http.StatusNotFound, "Blog post not found in trash")
+		return
+	} else if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to restore blog post")
+		return
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, restored); err != nil {
+		log.Printf("Failed to write response: %v", err)
+	}
+}
+
+// purgeBlogHandler permanently deletes a trashed blog post
+// @Summary Permanently delete a trashed blog post
+// @Description Remove a soft-deleted blog post for good, along with its image if no other post uses it
+// @Tags trash
+// @Produce json
+// @Security BearerAuth
+// @Param urlKeyword path string true "URL Keyword of the blog post"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /trash/{urlKeyword} [delete]
+func purgeBlogHandler(w http.ResponseWriter, r *http.Request) {
+	urlKeyword := strings.TrimPrefix(r.URL.Path, "/trash/")
+
+	var post BlogPost
+	var images []string
+	err := withTransaction(func(tx *sql.Tx) error {
+		var err error
+		post, err = getTrashedBlogPost(tx, urlKeyword)
+		if err != nil {
+			return err
+		}
+		if images, err = revisionImages(tx, post.ID); err != nil {
+			return err
+		}
+		if err := unindexPost(tx, post.ID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE FROM post_tags WHERE post_id = ?", post.ID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE FROM post_revisions WHERE post_id = ?", post.ID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE FROM slug_redirects WHERE new_slug = ?", post.UrlKeyword); err != nil {
+			return err
+		}
+		_, err = tx.Exec("DELETE FROM blog_posts WHERE id = ?", post.ID)
+		return err
+	})
+
+	if err == sql.ErrNoRows {
+		writeErrorResponse(w, http.StatusNotFound, "Blog post not found in trash")
+		return
+	} else if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to purge blog post")
+		return
+	}
+
+	for _, image := range append(images, post.Image) {
+		if err := removeUnusedImage(image); err != nil {
+			log.Printf("Failed to remove image %s: %v", image, err)
+		}
+	}
+
+	if err := writeJSONResponse(w, http.StatusOK, map[string]string{
+		"message": "Blog post permanently deleted",
+	}); err != nil {
+		log.Printf("Failed to write response: %v", err)
+	}
+}
+
+// revisionImages returns the images a post has had over its revisions
+func revisionImages(tx *sql.Tx, postID int64) ([]string, error) {
+	rows, err := tx.Query("SELECT DISTINCT image FROM post_revisions WHERE post_id = ? AND image != ''", postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var images []string
+	for rows.Next() {
+		var image string
+		if err := rows.Scan(&image); err != nil {
+			return nil, err
+		}
+		images = append(images, image)
+	}
+	return images, rows.Err()
+}
+
+// removeUnusedImage deletes an uploaded image once no post, revision or
+// author references it, so rolling back to an old revision never loses its
+// image. Paths outside the uploads directory are never touched.
+func removeUnusedImage(image string) error {
+	if image == "" || filepath.Dir(filepath.Clean(image)) != "uploads" {
+		return nil
+	}
+
+	var inUse bool
+	err := db.QueryRow(`SELECT EXISTS(SELECT 1 FROM blog_posts WHERE image = ?)
+		OR EXISTS(SELECT 1 FROM post_revisions WHERE image = ?)
+		OR EXISTS(SELECT 1 FROM authors WHERE avatar = ?)`, image, image, image).Scan(&inUse)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return nil
+	}
+
+	if err := os.Remove(image); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,376 @@
+package main
+
+import (
+	"bufio"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Password length limits. bcrypt ignores everything past 72 bytes, so longer
+// passwords are refused rather than silently truncated.
+const (
+	minPasswordLength = 8
+	maxPasswordLength = 72
+)
+
+var errEmailTaken = errors.New("a user with this email already exists")
+
+// User is a member of the editorial team who logs in with a password
+// @swagger:model
+type User struct {
+	ID    int64  `json:"id"`
+	Email string `json:"email"`
+	Name  string `json:"name"`
+	Role  string `json:"role" enums:"viewer,author,editor,admin"`
+
+	CreatedAt string `json:"created_at"`
+}
+
+// validateUser normalises and checks the fields of a new user
+func validateUser(email, name, role, password string) (string, string, error) {
+	address, err := mail.ParseAddress(strings.TrimSpace(email))
+	if err != nil || address.Name != "" {
+		return "", "", fmt.Errorf("a valid email address is required")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 100 {
+		return "", "", fmt.Errorf("name is required and cannot exceed 100 characters")
+	}
+	if _, ok := roleRank[role]; !ok {
+		return "", "", fmt.Errorf("invalid role: must be viewer, author, editor or admin")
+	}
+	if err := validatePassword(password); err != nil {
+		return "", "", err
+	}
+	return strings.ToLower(address.Address), name, nil
+}
+
+func validatePassword(password string) error {
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return fmt.Errorf("password must be between %d and %d characters long", minPasswordLength, maxPasswordLength)
+	}
+	return nil
+}
+
+// createUser adds a user whose fields passed validateUser, reporting
+// errEmailTaken if the email is already in use
+func createUser(email, name, role, password string) (User, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return User{}, err
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM users WHERE email = ?", email).Scan(&count); err != nil {
+		return User{}, err
+	}
+	if count > 0 {
+		return User{}, errEmailTaken
+	}
+
+	result, err := db.Exec("INSERT INTO users (email, name, role, password_hash) VALUES (?, ?, ?, ?)",
+		email, name, role, string(hash))
+	if err != nil {
+		return User{}, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return User{}, err
+	}
+	return getUser(id)
+}
+
+func getUser(id int64) (User, error) {
+	var user User
+	err := db.QueryRow("SELECT id, email, name, role, created_at FROM users WHERE id = ?", id).
+		Scan(&user.ID, &user.Email, &user.Name, &user.Role, &user.CreatedAt)
+	return user, err
+}
+
+// listUsers returns every user, oldest first
+func listUsers() ([]User, error) {
+	rows, err := db.Query("SELECT id, email, name, role, created_at FROM users ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []User{}
+	for rows.Next() {
+		var user User
+		if err := rows.Scan(&user.ID, &user.Email, &user.Name, &user.Role, &user.CreatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+// setPassword changes a user's password and ends their sessions, reporting
+// sql.ErrNoRows if there is no such user
+func setPassword(id int64, password string) error {
+	if err := validatePassword(password); err != nil {
+		return err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	return withTransaction(func(tx *sql.Tx) error {
+		result, err := tx.Exec("UPDATE users SET password_hash = ? WHERE id = ?", string(hash), id)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return sql.ErrNoRows
+		}
+		_, err = revokeUserSessions(tx, id)
+		return err
+	})
+}
+
+// usersRouter dispatches /users requests
+func usersRouter(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/users"), "/")
+
+	switch {
+	case path == "" && r.Method == http.MethodGet:
+		listUsersHandler(w, r)
+	case path == "" && r.Method == http.MethodPost:
+		createUserHandler(w, r)
+	case path == "":
+		w.Header().Set("Allow", "GET, POST")
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+	case strings.HasSuffix(path, "/sessions") && r.Method == http.MethodDelete:
+		revokeUserSessionsHandler(w, r)
+	case strings.HasSuffix(path, "/sessions"):
+		w.Header().Set("Allow", "DELETE")
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Method not allowed")
+	default:
+		writeErrorResponse(w, http.StatusNotFound, "Not found")
+	}
+}
+
+// listUsersHandler lists the users
+// @Summary List users
+// @Description List every user of the editorial team
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} User
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users [get]
+func listUsersHandler(w http.ResponseWriter, r *http.Request) {
+	users, err := listUsers()
+	if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Could not fetch users")
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, users)
+}
+
+// createUserHandler adds a user
+// @Summary Create a user
+// @Description Add a member of the editorial team who can log in at /auth/login
+// @Tags users
+// @Accept multipart/form-data,application/x-www-form-urlencoded
+// @Produce json
+// @Security BearerAuth
+// @Param email formData string true "Email address, used to log in"
+// @Param name formData string true "Display name"
+// @Param role formData string true "Role of the user" Enums(viewer, author, editor, admin)
+// @Param password formData string true "Password, 8 to 72 characters"
+// @Success 201 {object} User
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users [post]
+func createUserHandler(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(maxFileSize); err != nil && err != http.ErrNotMultipart {
+		writeErrorResponse(w, http.StatusBadRequest, "Failed to parse form data")
+		return
+	}
+
+	role := strings.TrimSpace(r.FormValue("role"))
+	password := r.FormValue("password")
+	email, name, err := validateUser(r.FormValue("email"), r.FormValue("name"), role, password)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := createUser(email, name, role, password)
+	if err == errEmailTaken {
+		writeErrorResponse(w, http.StatusConflict, err.Error())
+		return
+	} else if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to create user")
+		return
+	}
+	writeJSONResponse(w, http.StatusCreated, user)
+}
+
+// revokeUserSessionsHandler logs a user out everywhere
+// @Summary Revoke a user's sessions
+// @Description End every session of a user. Their access tokens stop working immediately and their refresh tokens can no longer be used.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID of the user"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/{id}/sessions [delete]
+func revokeUserSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	rawID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/users/"), "/sessions")
+	id, err := strconv.ParseInt(rawID, 10, 64)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "id must be a number")
+		return
+	}
+
+	if _, err := getUser(id); err == sql.ErrNoRows {
+		writeErrorResponse(w, http.StatusNotFound, "User not found")
+		return
+	} else if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Database error")
+		return
+	}
+
+	var revoked int64
+	err = withTransaction(func(tx *sql.Tx) error {
+		revoked, err = revokeUserSessions(tx, id)
+		return err
+	})
+	if err != nil {
+		fmt.Println(err)
+		writeErrorResponse(w, http.StatusInternalServerError, "Failed to revoke sessions")
+		return
+	}
+	writeJSONResponse(w, http.StatusOK, map[string]interface{}{
+		"message": "Sessions revoked",
+		"revoked": revoked,
+	})
+}
+
+// readPassword reads a password from the first line of standard input, so
+// it stays out of the shell history
+func readPassword() (string, error) {
+	fmt.Fprint(os.Stderr, "Password: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("no password given on standard input")
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// runUsersCommand manages users from the command line. Passwords are read
+// from standard input:
+//
+//	blogo users create -email jane@example.com -name "Jane Doe" -role editor
+//	blogo users list
+//	blogo users passwd 3
+//	blogo users revoke 3
+func runUsersCommand(args []string) error {
+	usage := fmt.Errorf("usage: users create -email EMAIL -name NAME -role ROLE | users list | users passwd ID | users revoke ID")
+	if len(args) == 0 {
+		return usage
+	}
+
+	switch args[0] {
+	case "create":
+		flags := flag.NewFlagSet("users create", flag.ContinueOnError)
+		email := flags.String("email", "", "email address, used to log in")
+		name := flags.String("name", "", "display name")
+		role := flags.String("role", RoleAuthor, "viewer, author, editor or admin")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		password, err := readPassword()
+		if err != nil {
+			return err
+		}
+		address, displayName, err := validateUser(*email, *name, *role, password)
+		if err != nil {
+			return err
+		}
+		user, err := createUser(address, displayName, *role, password)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created %s user %d for %s\n", user.Role, user.ID, user.Email)
+	case "list":
+		users, err := listUsers()
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tEMAIL\tNAME\tROLE\tCREATED")
+		for _, user := range users {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", user.ID, user.Email, user.Name, user.Role, user.CreatedAt)
+		}
+		return tw.Flush()
+	case "passwd", "revoke":
+		if len(args) != 2 {
+			return usage
+		}
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("id must be a number")
+		}
+		if args[0] == "passwd" {
+			password, err := readPassword()
+			if err != nil {
+				return err
+			}
+			if err := setPassword(id, password); err == sql.ErrNoRows {
+				return fmt.Errorf("no user with id %d", id)
+			} else if err != nil {
+				return err
+			}
+			fmt.Printf("Changed the password of user %d and ended their sessions\n", id)
+			return nil
+		}
+
+		if _, err := getUser(id); err == sql.ErrNoRows {
+			return fmt.Errorf("no user with id %d", id)
+		} else if err != nil {
+			return err
+		}
+		var revoked int64
+		err = withTransaction(func(tx *sql.Tx) error {
+			revoked, err = revokeUserSessions(tx, id)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Revoked %d sessions of user %d\n", revoked, id)
+	default:
+		return usage
+	}
+	return nil
+}